@@ -0,0 +1,21 @@
+package main
+
+import (
+	"github.com/kweaver-ai/idrm-go-frame/core/cdc"
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+	"github.com/kweaver-ai/idrm-go-frame/core/options"
+	"github.com/kweaver-ai/idrm-go-frame/core/store/gormx"
+	"github.com/kweaver-ai/idrm-go-frame/core/store/redis"
+	"github.com/kweaver-ai/idrm-go-frame/core/telemetry"
+)
+
+// catalog the config structs of the framework usable by -bind.
+var catalog = map[string]interface{}{
+	"cdc.SourceConf":    cdc.SourceConf{},
+	"gormx.Options":     gormx.Options{},
+	"options.DBOptions": options.DBOptions{},
+	"redis.RedisConf":   redis.RedisConf{},
+	"telemetry.Config":  telemetry.Config{},
+	"zapx.LogConfigs":   zapx.LogConfigs{},
+	"zapx.Options":      zapx.Options{},
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config"
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources/env"
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources/file"
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources/profile"
+	"github.com/kweaver-ai/idrm-go-frame/core/encoding"
+)
+
+// Report the issues of a config, File names its layers: config.yaml+config_prod.yaml.
+type Report struct {
+	File   string             `json:"file"`
+	Issues []config.LintIssue `json:"issues"`
+}
+
+// target a config to lint: a base file, alone or with one of its profiles.
+type target struct {
+	base    string
+	profile string
+}
+
+// Lint lints the config file path, or every config file of the directory path.
+// A profile file config_<env>.yaml is linted merged over its base config.yaml.
+func Lint(path string, schema *config.Schema, envPrefixes string) ([]Report, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if !e.IsDir() && supported(e.Name()) {
+				files = append(files, filepath.Join(path, e.Name()))
+			}
+		}
+	} else {
+		files = append(files, path)
+	}
+
+	var reports []Report
+	for _, t := range targets(files) {
+		srcs := []sources.Source{t.source()}
+		switch envPrefixes {
+		case "":
+		case "*":
+			srcs = append(srcs, env.NewSource())
+		default:
+			srcs = append(srcs, env.NewSource(strings.Split(envPrefixes, ",")...))
+		}
+		issues, err := config.LintSources(schema, srcs...)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.name(), err)
+		}
+		reports = append(reports, Report{File: t.name(), Issues: issues})
+	}
+	return reports, nil
+}
+
+func supported(name string) bool {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(name), "."))
+	return ext == "yml" || (ext != "" && encoding.GetCodec(ext) != nil)
+}
+
+// layered reports whether the profiles of a file are merged: yaml and json, read by the profile source.
+func layered(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// targets returns the bases, then each profile merged over its base.
+func targets(files []string) []target {
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+	var bases, profiles []target
+	seen := map[string]bool{}
+	for _, f := range files {
+		base, name := baseOf(f, exists)
+		if name == "" {
+			if !seen[f] {
+				seen[f] = true
+				bases = append(bases, target{base: f})
+			}
+			continue
+		}
+		profiles = append(profiles, target{base: base, profile: name})
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].name() < profiles[j].name() })
+	return append(bases, profiles...)
+}
+
+// baseOf returns the base file and the profile of a profile file, the longest existing base wins.
+func baseOf(path string, exists func(string) bool) (string, string) {
+	if !layered(path) {
+		return path, ""
+	}
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+	for i := strings.LastIndexByte(stem, '_'); i > len(filepath.Dir(path)); i = strings.LastIndexByte(stem[:i], '_') {
+		if base := stem[:i] + ext; exists(base) {
+			return base, stem[i+1:]
+		}
+	}
+	return path, ""
+}
+
+func (t target) name() string {
+	if t.profile == "" {
+		return t.base
+	}
+	ext := filepath.Ext(t.base)
+	return t.base + "+" + filepath.Base(strings.TrimSuffix(t.base, ext)+"_"+t.profile+ext)
+}
+
+func (t target) source() sources.Source {
+	if !layered(t.base) {
+		return file.NewSource(t.base)
+	}
+	if t.profile == profile.LocalProfile {
+		return profile.NewSource(t.base, profile.WithProfile(""))
+	}
+	return profile.NewSource(t.base, profile.WithProfile(t.profile), profile.WithoutLocal())
+}
+
+// Write writes the reports as text, one issue per line, or as json.
+func Write(w io.Writer, reports []Report, format string) error {
+	if format == FormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(reports)
+	}
+	for _, r := range reports {
+		if len(r.Issues) == 0 {
+			if _, err := fmt.Fprintf(w, "%s: ok\n", r.File); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, i := range r.Issues {
+			if _, err := fmt.Fprintf(w, "%s: %s [%s]\n", r.File, i, i.Kind); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config"
+)
+
+func TestTargets(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"config.yaml":       "name: a\n",
+		"config_prod.yaml":  "name: b\nport: 1\n",
+		"config_local.yaml": "name: c\n",
+		"app_extra.json":    `{"name":"d"}`,
+		"legacy.properties": "name=e\n",
+		"README.md":         "",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	schema := &config.Schema{
+		Type:                 config.SchemaType{config.TypeObject},
+		Properties:           map[string]*config.Schema{"name": {Type: config.SchemaType{config.TypeString}}},
+		AdditionalProperties: false,
+	}
+	reports, err := Lint(dir, schema, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]int{}
+	for _, r := range reports {
+		got[filepath.Base(r.File)] = len(r.Issues)
+	}
+	want := map[string]int{
+		"app_extra.json":                0,
+		"config.yaml":                   0,
+		"legacy.properties":             0,
+		"config.yaml+config_local.yaml": 0,
+		// port is unknown
+		"config.yaml+config_prod.yaml": 1,
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
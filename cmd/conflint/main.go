@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config"
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+)
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var args = &CmdArg{}
+
+type CmdArg struct {
+	Path   string `json:"path"`
+	Bind   string `json:"bind"`
+	Schema string `json:"schema"`
+	Env    string `json:"env"`
+	Format string `json:"format"`
+}
+
+func (c *CmdArg) Check() error {
+	if c.Path == "" && c.Schema == "" {
+		return fmt.Errorf("'path' and 'schema' can't be both empty")
+	}
+	switch c.Format {
+	case FormatText, FormatJSON:
+	default:
+		return fmt.Errorf("'format' must be one of text, json")
+	}
+	return nil
+}
+
+func init() {
+	names := make([]string, 0, len(catalog))
+	for name := range catalog {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	flag.StringVar(&args.Path, "path", "", "需要检查的配置文件或目录， 例如: -path ./cmd/server/config")
+	flag.StringVar(&args.Bind, "bind", "", "配置 key 与结构体的对应关系，逗号分隔，key 为空表示整个配置， 例如: -bind db=gormx.Options,telemetry=telemetry.Config,=zapx.LogConfigs\n可用的结构体: "+strings.Join(names, ", "))
+	flag.StringVar(&args.Schema, "schema", "", "JSON Schema 输出文件路径，- 表示标准输出")
+	flag.StringVar(&args.Env, "env", "", "同时加载的环境变量前缀，逗号分隔，与服务的 env.NewSource 一致，* 表示全部环境变量，用于检查 ${...} 占位符")
+	flag.StringVar(&args.Format, "format", FormatText, "检查结果的输出格式: text, json")
+}
+
+func main() {
+	flag.Parse()
+	if err := args.Check(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+	if err := bind(args.Bind); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+	initLogger()
+	schema := config.RegisteredSchema()
+
+	if args.Schema != "" {
+		if err := writeSchema(args.Schema, schema); err != nil {
+			fmt.Fprintf(os.Stderr, "输出 JSON Schema 失败:%v\n", err)
+			os.Exit(1)
+		}
+	}
+	if args.Path == "" {
+		return
+	}
+	reports, err := Lint(args.Path, schema, args.Env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "检查配置失败:%v\n", err)
+		os.Exit(1)
+	}
+	if err := Write(os.Stdout, reports, args.Format); err != nil {
+		fmt.Fprintf(os.Stderr, "输出检查结果失败:%v\n", err)
+		os.Exit(1)
+	}
+	for _, r := range reports {
+		if len(r.Issues) > 0 {
+			os.Exit(1)
+		}
+	}
+}
+
+// bind registers the structs of the catalog, "key=type,..."
+func bind(spec string) error {
+	if spec == "" {
+		return nil
+	}
+	for _, item := range strings.Split(spec, ",") {
+		key, name, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			return fmt.Errorf("'bind' item %q must be key=type", item)
+		}
+		v, ok := catalog[name]
+		if !ok {
+			return fmt.Errorf("'bind' unknown type %q", name)
+		}
+		config.RegisterSchema(key, v)
+	}
+	return nil
+}
+
+// initLogger writes the warnings of the config package to stderr.
+func initLogger() {
+	zapx.RegisterWriteSyncer("stderr", os.Stderr)
+	opts := zapx.DefaultOptions()
+	opts.EnableCaller = false
+	opts.CoreConfigs[0].CoreType = "stderr"
+	opts.CoreConfigs[0].LogLevel = zapx.WarnLevel.String()
+	zapx.Load(opts)
+}
+
+func writeSchema(path string, schema *config.Schema) error {
+	if schema == nil {
+		return fmt.Errorf("no struct bound, see -bind")
+	}
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
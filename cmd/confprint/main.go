@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config"
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources/env"
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources/profile"
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+var args = &CmdArg{}
+
+type CmdArg struct {
+	Path    string `json:"path"`
+	Profile string `json:"profile"`
+	Local   bool   `json:"local"`
+	Env     string `json:"env"`
+	Raw     bool   `json:"raw"`
+	Format  string `json:"format"`
+	Out     string `json:"out"`
+}
+
+func (c *CmdArg) Check() error {
+	if c.Path == "" {
+		return fmt.Errorf("'path' can't be empty")
+	}
+	switch c.Format {
+	case FormatYAML, FormatJSON:
+	default:
+		return fmt.Errorf("'format' must be one of yaml, json")
+	}
+	return nil
+}
+
+func init() {
+	flag.StringVar(&args.Path, "path", "config.yaml", "基础配置文件路径， 例如: -path ./cmd/server/config/config.yaml")
+	flag.StringVar(&args.Profile, "profile", os.Getenv(sources.ProjectEnvKey), "叠加的环境配置， 例如: -profile prod，默认取环境变量 "+sources.ProjectEnvKey)
+	flag.BoolVar(&args.Local, "local", true, "是否叠加 config_local.yaml")
+	flag.StringVar(&args.Env, "env", "", "同时加载的环境变量前缀，逗号分隔，与服务的 env.NewSource 一致， 例如: -env DB_,REDIS_")
+	flag.BoolVar(&args.Raw, "raw", false, "不替换 ${...} 占位符")
+	flag.StringVar(&args.Format, "format", FormatYAML, "输出格式: yaml, json")
+	flag.StringVar(&args.Out, "out", "", "输出文件路径，为空时输出到标准输出")
+}
+
+func main() {
+	flag.Parse()
+	if err := args.Check(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+	initLogger()
+	resolved, err := resolve(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "解析配置失败:%v\n", err)
+		os.Exit(1)
+	}
+
+	var w io.Writer = os.Stdout
+	if args.Out != "" {
+		f, err := os.Create(args.Out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "创建输出文件失败:%v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := write(w, resolved, args.Format); err != nil {
+		fmt.Fprintf(os.Stderr, "输出配置失败:%v\n", err)
+		os.Exit(1)
+	}
+}
+
+// initLogger writes the warnings of the config package to stderr, the config is printed to stdout.
+func initLogger() {
+	zapx.RegisterWriteSyncer("stderr", os.Stderr)
+	opts := zapx.DefaultOptions()
+	opts.EnableCaller = false
+	opts.CoreConfigs[0].CoreType = "stderr"
+	opts.CoreConfigs[0].LogLevel = zapx.WarnLevel.String()
+	zapx.Load(opts)
+}
+
+// resolve merges the layers, then replaces the placeholders as config.Load does.
+func resolve(c *CmdArg) (map[string]interface{}, error) {
+	if c.Raw {
+		return profile.Resolve(c.Path, c.Profile, c.Local)
+	}
+	opts := []profile.Option{profile.WithProfile(c.Profile)}
+	if !c.Local {
+		opts = append(opts, profile.WithoutLocal())
+	}
+	srcs := []sources.Source{profile.NewSource(c.Path, opts...)}
+	if c.Env != "" {
+		srcs = append(srcs, env.NewSource(strings.Split(c.Env, ",")...))
+	}
+	conf := config.New(config.WithSource(srcs...))
+	defer conf.Close()
+	if err := conf.Load(); err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := conf.Scan(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func write(w io.Writer, v map[string]interface{}, format string) error {
+	if format == FormatJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	defer enc.Close()
+	return enc.Encode(v)
+}
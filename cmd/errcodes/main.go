@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+var args = &CmdArg{}
+
+type CmdArg struct {
+	Path        string `json:"path"`
+	Format      string `json:"format"`
+	Out         string `json:"out"`
+	ServiceName string `json:"service_name"`
+	AllowDup    bool   `json:"allow_dup"`
+}
+
+func (c *CmdArg) Check() error {
+	if c.Path == "" {
+		return fmt.Errorf("'path' can't be empty")
+	}
+	switch c.Format {
+	case FormatJSON, FormatMarkdown, FormatOpenAPI:
+	default:
+		return fmt.Errorf("'format' must be one of json, markdown, openapi")
+	}
+	return nil
+}
+
+func init() {
+	flag.StringVar(&args.Path, "path", ".", "需要扫描的项目路径， 例如: -path ./data-view")
+	flag.StringVar(&args.Format, "format", FormatJSON, "输出格式: json, markdown, openapi")
+	flag.StringVar(&args.Out, "out", "", "输出文件路径，为空时输出到标准输出")
+	flag.StringVar(&args.ServiceName, "service-name", "", "服务名称，用于文档标题， 例如: data-view")
+	flag.BoolVar(&args.AllowDup, "allow-dup", false, "存在重复错误码时不返回失败")
+}
+
+func main() {
+	flag.Parse()
+	if err := args.Check(); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(2)
+	}
+	report, err := Scan(args.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "扫描错误码失败:%v\n", err)
+		os.Exit(1)
+	}
+
+	var w io.Writer = os.Stdout
+	if args.Out != "" {
+		f, err := os.Create(args.Out)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "创建输出文件失败:%v\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := Write(w, report, args.Format, args.ServiceName); err != nil {
+		fmt.Fprintf(os.Stderr, "输出错误码失败:%v\n", err)
+		os.Exit(1)
+	}
+
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(os.Stderr, "警告: %s\n", warning)
+	}
+	for _, d := range report.Duplicates {
+		fmt.Fprintf(os.Stderr, "重复的错误码 %s:\n", d.Code)
+		for _, def := range d.Definitions {
+			fmt.Fprintf(os.Stderr, "\t%s\n", def.Position)
+		}
+	}
+	if len(report.Duplicates) > 0 && !args.AllowDup {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+	FormatOpenAPI  = "openapi"
+)
+
+// Write renders the report in format.
+func Write(w io.Writer, report *Report, format, serviceName string) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, report)
+	case FormatMarkdown:
+		return writeMarkdown(w, report, serviceName)
+	case FormatOpenAPI:
+		return writeJSON(w, openAPI(report, serviceName))
+	}
+	return fmt.Errorf("unsupported format %q, use json, markdown or openapi", format)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func writeMarkdown(w io.Writer, report *Report, serviceName string) error {
+	title := "错误码"
+	if serviceName != "" {
+		title = serviceName + " " + title
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", title)
+	pkg, first := "", true
+	for _, c := range sortByPackage(report.Codes) {
+		if c.Package != pkg || first {
+			first = false
+			pkg = c.Package
+			fmt.Fprintf(&b, "\n## %s\n\n", displayPackage(pkg))
+			b.WriteString("| 错误码 | 描述 | 原因 | 解决方法 |\n|------|------|------|------|\n")
+		}
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", c.Code, cell(c.Description), cell(c.Cause), cell(c.Solution))
+	}
+	if len(report.Duplicates) > 0 {
+		b.WriteString("\n## 重复的错误码\n\n")
+		for _, d := range report.Duplicates {
+			fmt.Fprintf(&b, "- `%s`\n", d.Code)
+			for _, def := range d.Definitions {
+				fmt.Fprintf(&b, "  - %s\n", def.Position)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// openAPI builds an OpenAPI 3 document holding the HttpError schema, its code enum and one example per code.
+func openAPI(report *Report, serviceName string) map[string]any {
+	var codes []string
+	examples := make(map[string]any)
+	seen := make(map[string]bool)
+	for _, c := range report.Codes {
+		if seen[c.Code] {
+			continue
+		}
+		seen[c.Code] = true
+		codes = append(codes, c.Code)
+		value := map[string]any{"code": c.Code, "description": c.Description}
+		if c.Cause != "" {
+			value["cause"] = c.Cause
+		}
+		if c.Solution != "" {
+			value["solution"] = c.Solution
+		}
+		examples[c.Code] = map[string]any{"summary": c.Description, "value": value}
+	}
+	title := "error codes"
+	if serviceName != "" {
+		title = serviceName + " " + title
+	}
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info":    map[string]any{"title": title, "version": "1.0.0"},
+		"paths":   map[string]any{},
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"HttpError": map[string]any{
+					"type":     "object",
+					"required": []string{"code", "description"},
+					"properties": map[string]any{
+						"code":        map[string]any{"type": "string", "description": "错误码，格式: 服务名.模块.错误", "enum": codes},
+						"description": map[string]any{"type": "string", "description": "错误描述"},
+						"solution":    map[string]any{"type": "string", "description": "错误处理办法"},
+						"cause":       map[string]any{"type": "string", "description": "错误原因"},
+						"detail":      map[string]any{"description": "错误详情, 一般是json对象"},
+					},
+				},
+			},
+			"examples": examples,
+		},
+	}
+}
+
+func sortByPackage(codes []*CodeDef) []*CodeDef {
+	out := append([]*CodeDef(nil), codes...)
+	// codes are sorted by code, a stable sort keeps that order inside a package
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Package < out[j].Package })
+	return out
+}
+
+func displayPackage(pkg string) string {
+	if pkg == "" || pkg == "." {
+		return "/"
+	}
+	return pkg
+}
+
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
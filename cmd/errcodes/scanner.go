@@ -0,0 +1,373 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	errorxImportPath  = "github.com/kweaver-ai/idrm-go-frame/core/errorx"
+	agcodesImportPath = "github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+
+	KindErrorx  = "errorx"
+	KindAgcodes = "agcodes"
+)
+
+// CodeDef 一个错误码的定义位置
+type CodeDef struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Cause       string `json:"cause,omitempty"`
+	Solution    string `json:"solution,omitempty"`
+	Kind        string `json:"kind"`
+	Package     string `json:"package"`
+	Position    string `json:"position"`
+}
+
+// Duplicate 重复定义的错误码
+type Duplicate struct {
+	Code        string     `json:"code"`
+	Definitions []*CodeDef `json:"definitions"`
+}
+
+// Report 扫描结果
+type Report struct {
+	Codes      []*CodeDef   `json:"codes"`
+	Duplicates []*Duplicate `json:"duplicates,omitempty"`
+	// Warnings calls whose code could not be resolved statically
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// packageFiles the parsed files of one directory
+type packageFiles struct {
+	dir    string
+	fset   *token.FileSet
+	files  []*ast.File
+	consts map[string]string
+	// modules errorx.New module variables and their code prefix
+	modules map[string]string
+}
+
+// Scan statically scans the go files under root for errorx and agcodes code definitions.
+func Scan(root string) (*Report, error) {
+	pkgs, err := parseDirs(root)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	for _, pkg := range pkgs {
+		pkg.collectDecls()
+		for _, f := range pkg.files {
+			pkg.collectCodes(root, f, report)
+		}
+	}
+	sort.SliceStable(report.Codes, func(i, j int) bool {
+		if report.Codes[i].Code != report.Codes[j].Code {
+			return report.Codes[i].Code < report.Codes[j].Code
+		}
+		return report.Codes[i].Position < report.Codes[j].Position
+	})
+	report.Duplicates = findDuplicates(report.Codes)
+	return report, nil
+}
+
+func parseDirs(root string) ([]*packageFiles, error) {
+	byDir := make(map[string]*packageFiles)
+	var dirs []string
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name := info.Name()
+		if info.IsDir() {
+			if path != root && (name == "vendor" || name == "testdata" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		pkg, ok := byDir[dir]
+		if !ok {
+			pkg = &packageFiles{dir: dir, fset: token.NewFileSet(), consts: map[string]string{}, modules: map[string]string{}}
+			byDir[dir] = pkg
+			dirs = append(dirs, dir)
+		}
+		f, err := parser.ParseFile(pkg.fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		pkg.files = append(pkg.files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(dirs)
+	pkgs := make([]*packageFiles, 0, len(dirs))
+	for _, dir := range dirs {
+		pkgs = append(pkgs, byDir[dir])
+	}
+	return pkgs, nil
+}
+
+// collectDecls records the string constants and the errorx module variables of the package.
+func (p *packageFiles) collectDecls() {
+	// constants may refer to each other across files, resolve until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for _, f := range p.files {
+			for _, decl := range f.Decls {
+				gen, ok := decl.(*ast.GenDecl)
+				if !ok || gen.Tok != token.CONST {
+					continue
+				}
+				for _, spec := range gen.Specs {
+					vs := spec.(*ast.ValueSpec)
+					for i, name := range vs.Names {
+						if _, done := p.consts[name.Name]; done || i >= len(vs.Values) {
+							continue
+						}
+						if s, ok := p.stringValue(vs.Values[i]); ok {
+							p.consts[name.Name] = s
+							changed = true
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, f := range p.files {
+		errorxName := importName(f, errorxImportPath)
+		if errorxName == "" {
+			continue
+		}
+		ast.Inspect(f, func(n ast.Node) bool {
+			var names []*ast.Ident
+			var values []ast.Expr
+			switch s := n.(type) {
+			case *ast.ValueSpec:
+				names, values = s.Names, s.Values
+			case *ast.AssignStmt:
+				for _, l := range s.Lhs {
+					if id, ok := l.(*ast.Ident); ok {
+						names = append(names, id)
+					}
+				}
+				values = s.Rhs
+			default:
+				return true
+			}
+			for i, name := range names {
+				if i >= len(values) {
+					break
+				}
+				if prefix, ok := p.modulePrefix(values[i], errorxName); ok {
+					p.modules[name.Name] = prefix
+				}
+			}
+			return true
+		})
+	}
+}
+
+// modulePrefix resolves errorx.New("Prefix.") expressions.
+func (p *packageFiles) modulePrefix(expr ast.Expr, errorxName string) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || !isSelector(call.Fun, errorxName, "New") || len(call.Args) != 1 {
+		return "", false
+	}
+	prefix, ok := p.stringValue(call.Args[0])
+	if !ok {
+		return "", false
+	}
+	if prefix == "" {
+		// errorx.Module.Solution falls back to the public prefix
+		prefix = "Basic.Public."
+	}
+	return prefix, true
+}
+
+func (p *packageFiles) collectCodes(root string, f *ast.File, report *Report) {
+	errorxName := importName(f, errorxImportPath)
+	agcodesName := importName(f, agcodesImportPath)
+	if errorxName == "" && agcodesName == "" {
+		return
+	}
+	pkgPath, _ := filepath.Rel(root, p.dir)
+	pkgPath = filepath.ToSlash(pkgPath)
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		pos := p.fset.Position(call.Pos())
+		position := fmt.Sprintf("%s:%d", filepath.ToSlash(relPath(root, pos.Filename)), pos.Line)
+
+		var def *CodeDef
+		switch {
+		case agcodesName != "" && isSelector(call.Fun, agcodesName, "New"):
+			def = p.agcodesDef(call, report, position)
+		case errorxName != "" && (sel.Sel.Name == "Solution" || sel.Sel.Name == "Cause" || sel.Sel.Name == "Description"):
+			def = p.errorxDef(call, sel, errorxName, report, position)
+		}
+		if def != nil {
+			def.Package = pkgPath
+			def.Position = position
+			report.Codes = append(report.Codes, def)
+		}
+		return true
+	})
+}
+
+// agcodesDef agcodes.New(code, description, cause, solution, detail, link)
+func (p *packageFiles) agcodesDef(call *ast.CallExpr, report *Report, position string) *CodeDef {
+	if len(call.Args) < 4 {
+		return nil
+	}
+	code, ok := p.stringValue(call.Args[0])
+	if !ok {
+		report.Warnings = append(report.Warnings, position+": agcodes.New code is not a constant")
+		return nil
+	}
+	desc, _ := p.stringValue(call.Args[1])
+	cause, _ := p.stringValue(call.Args[2])
+	solution, _ := p.stringValue(call.Args[3])
+	return &CodeDef{Code: code, Description: desc, Cause: cause, Solution: solution, Kind: KindAgcodes}
+}
+
+// errorxDef module.Solution(code, desc, cause, solution), module.Cause(code, desc, cause), module.Description(code, desc)
+func (p *packageFiles) errorxDef(call *ast.CallExpr, sel *ast.SelectorExpr, errorxName string, report *Report, position string) *CodeDef {
+	var prefix string
+	switch x := sel.X.(type) {
+	case *ast.Ident:
+		var ok bool
+		if prefix, ok = p.modules[x.Name]; !ok {
+			return nil
+		}
+	default:
+		var ok bool
+		if prefix, ok = p.modulePrefix(sel.X, errorxName); !ok {
+			return nil
+		}
+	}
+	want := map[string]int{"Solution": 4, "Cause": 3, "Description": 2}[sel.Sel.Name]
+	if len(call.Args) != want {
+		return nil
+	}
+	values := make([]string, 4)
+	for i, arg := range call.Args {
+		s, ok := p.stringValue(arg)
+		if !ok && i == 0 {
+			report.Warnings = append(report.Warnings, position+": errorx code is not a constant")
+			return nil
+		}
+		values[i] = s
+	}
+	return &CodeDef{Code: prefix + values[0], Description: values[1], Cause: values[2], Solution: values[3], Kind: KindErrorx}
+}
+
+// stringValue evaluates string literals, package constants and their concatenation.
+func (p *packageFiles) stringValue(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(e.Value)
+		return s, err == nil
+	case *ast.Ident:
+		s, ok := p.consts[e.Name]
+		return s, ok
+	case *ast.ParenExpr:
+		return p.stringValue(e.X)
+	case *ast.BinaryExpr:
+		if e.Op != token.ADD {
+			return "", false
+		}
+		l, ok := p.stringValue(e.X)
+		if !ok {
+			return "", false
+		}
+		r, ok := p.stringValue(e.Y)
+		return l + r, ok
+	}
+	return "", false
+}
+
+// findDuplicates reports codes registered more than once with errorx, which panics at init,
+// or defined in more than one package.
+func findDuplicates(codes []*CodeDef) []*Duplicate {
+	byCode := make(map[string][]*CodeDef)
+	var order []string
+	for _, c := range codes {
+		if _, ok := byCode[c.Code]; !ok {
+			order = append(order, c.Code)
+		}
+		byCode[c.Code] = append(byCode[c.Code], c)
+	}
+	var dups []*Duplicate
+	for _, code := range order {
+		defs := byCode[code]
+		if len(defs) < 2 {
+			continue
+		}
+		errorxDefs := 0
+		pkgs := make(map[string]bool)
+		for _, d := range defs {
+			if d.Kind == KindErrorx {
+				errorxDefs++
+			}
+			pkgs[d.Package] = true
+		}
+		if errorxDefs > 1 || len(pkgs) > 1 {
+			dups = append(dups, &Duplicate{Code: code, Definitions: defs})
+		}
+	}
+	return dups
+}
+
+// importName returns the local name of the import path in f, "" when it is not imported.
+func importName(f *ast.File, path string) string {
+	for _, imp := range f.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		if p != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return path[strings.LastIndex(path, "/")+1:]
+	}
+	return ""
+}
+
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != name {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == pkg
+}
+
+func relPath(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScan(t *testing.T) {
+	report, err := Scan("testdata/mod")
+	require.NoError(t, err)
+
+	codes := make(map[string]*CodeDef)
+	for _, c := range report.Codes {
+		codes[c.Code+"@"+c.Package] = c
+	}
+	require.Len(t, report.Codes, 5)
+	assert.Equal(t, "请检查后重试。", codes["DataView.FormView.NotFound@a"].Solution)
+	assert.Equal(t, "名称重复", codes["DataView.FormView.Exists@a"].Cause)
+	assert.Equal(t, KindErrorx, codes["DataView.Inline.Bad@a"].Kind)
+	assert.Equal(t, KindAgcodes, codes["DataView.Custom@a"].Kind)
+	assert.Equal(t, "b/errors.go:7", codes["DataView.FormView.NotFound@b"].Position)
+
+	require.Len(t, report.Duplicates, 1)
+	assert.Equal(t, "DataView.FormView.NotFound", report.Duplicates[0].Code)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, report, FormatOpenAPI, "data-view"))
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	enum := doc["components"].(map[string]any)["schemas"].(map[string]any)["HttpError"].(map[string]any)["properties"].(map[string]any)["code"].(map[string]any)["enum"]
+	assert.Len(t, enum, 4)
+
+	buf.Reset()
+	require.NoError(t, Write(&buf, report, FormatMarkdown, "data-view"))
+	assert.Contains(t, buf.String(), "| `DataView.Inline.Bad` | 错误 |  |  |")
+	assert.Contains(t, buf.String(), "## 重复的错误码")
+}
@@ -0,0 +1,18 @@
+package a
+
+import (
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+)
+
+const prefix = "DataView."
+
+var (
+	formView = errorx.New(prefix + "FormView.")
+
+	FormViewNotFound = formView.Solution("NotFound", "逻辑视图 [id] 不存在。", "", "请检查后重试。")
+	FormViewExists   = formView.Cause("Exists", "逻辑视图已存在", "名称重复")
+	Inline           = errorx.New("DataView.Inline.").Description("Bad", "错误")
+
+	Custom = agcodes.New("DataView.Custom", "自定义", "", "", nil, "")
+)
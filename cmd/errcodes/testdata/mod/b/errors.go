@@ -0,0 +1,7 @@
+package b
+
+import errs "github.com/kweaver-ai/idrm-go-frame/core/errorx"
+
+var m = errs.New("DataView.FormView.")
+
+var NotFound = m.Description("NotFound", "重复定义")
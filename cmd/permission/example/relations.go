@@ -3,18 +3,13 @@ package example
 import (
 	"fmt"
 
+	"github.com/kweaver-ai/idrm-go-frame/core/permission"
 	"github.com/kweaver-ai/idrm-go-frame/core/transport/rest/ginx"
 
 	"github.com/gin-gonic/gin"
 )
 
-type MenuResource struct {
-	ServiceName string `json:"service_name"`
-	Path        string `json:"path"`
-	Method      string `json:"method"`
-	Action      string `json:"action"`
-	Resource    string `json:"resource"`
-}
+type MenuResource = permission.MenuResource
 
 func ServiceACRegister(engine *gin.Engine, rs []*MenuResource) {
 	serviceName := "data-view"
@@ -24,6 +19,11 @@ func ServiceACRegister(engine *gin.Engine, rs []*MenuResource) {
 	})
 }
 
+// MenuResources returns the generated relations, pass them to permission.Middleware to enforce them.
+func MenuResources() []*MenuResource {
+	return menuResources
+}
+
 var menuResources []*MenuResource
 
 func init() {
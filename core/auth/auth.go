@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// Options jwt 签发与校验配置
+type Options struct {
+	// Secret HS256 密钥，JWKSFile 为空时使用
+	Secret string `json:"secret"       mapstructure:"secret"`
+	Issuer string `json:"issuer"       mapstructure:"issuer"`
+	// Expire 签发 token 的有效期
+	Expire time.Duration `json:"expire"       mapstructure:"expire"`
+	// JWKSFile 密钥文件，支持 oct(HS256) 与 RSA(RS256) key，替换文件即可轮换密钥
+	JWKSFile string `json:"jwks-file"    mapstructure:"jwks-file"`
+	// SigningKeyID 签发使用的 kid，为空时使用文件中第一个带私钥的 key
+	SigningKeyID string `json:"signing-kid"  mapstructure:"signing-kid"`
+	// Leeway 校验过期时间时允许的时钟偏差
+	Leeway time.Duration `json:"leeway"       mapstructure:"leeway"`
+}
+
+// Manager signs and verifies tokens.
+type Manager struct {
+	opts Options
+	keys *KeySet
+}
+
+// NewManager create a Manager with the given options.
+func NewManager(opts Options) (*Manager, error) {
+	var keys *KeySet
+	if opts.JWKSFile != "" {
+		var err error
+		if keys, err = NewKeySet(opts.JWKSFile, opts.SigningKeyID); err != nil {
+			return nil, err
+		}
+	} else if opts.Secret != "" {
+		keys = NewStaticKeySet(opts.Secret)
+	} else {
+		return nil, errors.New("jwt secret or jwks file is required")
+	}
+	return &Manager{opts: opts, keys: keys}, nil
+}
+
+// KeySet returns the keys in use, call KeySet().Watch to enable rotation.
+func (m *Manager) KeySet() *KeySet {
+	return m.keys
+}
+
+// Sign issues a token for the claims, issuer and expiry are filled from the options when empty.
+func (m *Manager) Sign(claims *Claims) (string, error) {
+	key, err := m.keys.Active()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	c := *claims
+	if c.Issuer == "" {
+		c.Issuer = m.opts.Issuer
+	}
+	if c.IssuedAt == nil {
+		c.IssuedAt = jwt.NewNumericDate(now)
+	}
+	if c.ExpiresAt == nil && m.opts.Expire > 0 {
+		c.ExpiresAt = jwt.NewNumericDate(now.Add(m.opts.Expire))
+	}
+
+	token := jwt.NewWithClaims(signingMethod(key.Algorithm), &c)
+	if key.ID != "" {
+		token.Header["kid"] = key.ID
+	}
+	return token.SignedString(key.signKey())
+}
+
+// Parse verifies the token and returns its claims.
+func (m *Manager) Parse(tokenString string) (*Claims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{AlgHS256, AlgRS256}),
+		jwt.WithLeeway(m.opts.Leeway),
+	}
+	if m.opts.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(m.opts.Issuer))
+	}
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, m.keyFunc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (m *Manager) keyFunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := m.keys.Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("unexpected signing method %s for kid %q", token.Method.Alg(), kid)
+	}
+	return key.verifyKey(), nil
+}
+
+func signingMethod(alg string) jwt.SigningMethod {
+	if alg == AlgRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodHS256
+}
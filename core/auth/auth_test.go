@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManagerHS256(t *testing.T) {
+	m, err := NewManager(Options{Secret: "secret", Issuer: "idrm", Expire: time.Minute})
+	require.NoError(t, err)
+
+	token, err := m.Sign(&Claims{UserID: "u1", Roles: []string{"admin"}})
+	require.NoError(t, err)
+
+	claims, err := m.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", claims.UserID)
+	assert.Equal(t, []string{"admin"}, claims.Roles)
+	assert.Equal(t, "idrm", claims.Issuer)
+
+	other, _ := NewManager(Options{Secret: "other", Issuer: "idrm"})
+	_, err = other.Parse(token)
+	assert.Error(t, err)
+
+	expired, err := m.Sign(&Claims{UserID: "u1", RegisteredClaims: jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+	}})
+	require.NoError(t, err)
+	_, err = m.Parse(expired)
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+}
+
+func TestManagerJWKSRotation(t *testing.T) {
+	k1, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	k2, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeJWKS(t, path, rsaJWK("k1", k1, true))
+
+	m, err := NewManager(Options{JWKSFile: path})
+	require.NoError(t, err)
+	old, err := m.Sign(&Claims{UserID: "u1"})
+	require.NoError(t, err)
+
+	// rotate: k2 signs, k1 is kept for verification only
+	writeJWKS(t, path, rsaJWK("k2", k2, true), rsaJWK("k1", k1, false))
+	require.NoError(t, m.KeySet().Reload())
+
+	current, err := m.Sign(&Claims{UserID: "u2"})
+	require.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(current, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "k2", parsed.Header["kid"])
+
+	for _, token := range []string{old, current} {
+		_, err := m.Parse(token)
+		assert.NoError(t, err)
+	}
+
+	// k1 retired
+	writeJWKS(t, path, rsaJWK("k2", k2, true))
+	require.NoError(t, m.KeySet().Reload())
+	_, err = m.Parse(old)
+	assert.Error(t, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m, err := NewManager(Options{Secret: "secret"})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(Middleware(m, SkipPaths("/health")))
+	r.GET("/me", func(c *gin.Context) {
+		claims, _ := FromContext(c)
+		token, _ := TokenFromContext(c.Request.Context())
+		c.JSON(http.StatusOK, gin.H{"uid": claims.UserID, "forwarded": Headers(c.Request.Context(), nil)["Authorization"] == "Bearer "+token})
+	})
+	r.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/me", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "Public.NotAuthorized")
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	token, _ := m.Sign(&Claims{UserID: "u1"})
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"uid":"u1","forwarded":true}`, w.Body.String())
+}
+
+func rsaJWK(kid string, key *rsa.PrivateKey, private bool) jwk {
+	enc := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+	k := jwk{Kty: "RSA", Kid: kid, N: enc(key.N), E: enc(big.NewInt(int64(key.E)))}
+	if private {
+		k.D, k.P, k.Q = enc(key.D), enc(key.Primes[0]), enc(key.Primes[1])
+	}
+	return k
+}
+
+func writeJWKS(t *testing.T, path string, keys ...jwk) {
+	data, err := json.Marshal(jwks{Keys: keys})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
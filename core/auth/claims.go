@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// ClaimsKey gin context key of the verified claims
+	ClaimsKey = "auth.claims"
+	// TokenKey gin context key of the raw bearer token
+	TokenKey = "auth.token"
+)
+
+// Claims is the typed payload carried in the token.
+type Claims struct {
+	UserID   string         `json:"uid,omitempty"`
+	UserName string         `json:"name,omitempty"`
+	Roles    []string       `json:"roles,omitempty"`
+	Extra    map[string]any `json:"ext,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type claimsCtxKey struct{}
+
+type tokenCtxKey struct{}
+
+// NewContext returns a copy of ctx carrying the claims and the raw token.
+func NewContext(ctx context.Context, claims *Claims, token string) context.Context {
+	ctx = context.WithValue(ctx, claimsCtxKey{}, claims)
+	return context.WithValue(ctx, tokenCtxKey{}, token)
+}
+
+// FromContext returns the claims stored by the middleware.
+func FromContext(ctx context.Context) (*Claims, bool) {
+	if c, ok := ctx.(*gin.Context); ok {
+		if v, exists := c.Get(ClaimsKey); exists {
+			claims, ok := v.(*Claims)
+			return claims, ok
+		}
+		ctx = c.Request.Context()
+	}
+	claims, ok := ctx.Value(claimsCtxKey{}).(*Claims)
+	return claims, ok
+}
+
+// TokenFromContext returns the raw bearer token stored by the middleware.
+func TokenFromContext(ctx context.Context) (string, bool) {
+	if c, ok := ctx.(*gin.Context); ok {
+		if v := c.GetString(TokenKey); v != "" {
+			return v, true
+		}
+		ctx = c.Request.Context()
+	}
+	token, ok := ctx.Value(tokenCtxKey{}).(string)
+	return token, ok && token != ""
+}
@@ -0,0 +1,240 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Key is a single verification (and optionally signing) key.
+type Key struct {
+	ID         string
+	Algorithm  string
+	Secret     []byte          // HS256
+	PublicKey  *rsa.PublicKey  // RS256
+	PrivateKey *rsa.PrivateKey // RS256, only present on the issuing side
+}
+
+func (k *Key) canSign() bool {
+	return len(k.Secret) > 0 || k.PrivateKey != nil
+}
+
+func (k *Key) verifyKey() any {
+	if k.PublicKey != nil {
+		return k.PublicKey
+	}
+	return k.Secret
+}
+
+func (k *Key) signKey() any {
+	if k.PrivateKey != nil {
+		return k.PrivateKey
+	}
+	return k.Secret
+}
+
+// jwk is the json web key representation, see RFC 7517.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	K   string `json:"k"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	D   string `json:"d"`
+	P   string `json:"p"`
+	Q   string `json:"q"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// KeySet holds the keys loaded from a JWKS file.
+// The file can be replaced at any time to rotate keys, new tokens are
+// signed with the active key while tokens signed by any key still present
+// in the file keep verifying.
+type KeySet struct {
+	path     string
+	activeID string
+
+	mu     sync.RWMutex
+	keys   map[string]*Key
+	active *Key
+}
+
+// NewKeySet loads a JWKS file. activeID chooses the signing key, when empty
+// the first key holding private material is used.
+func NewKeySet(path, activeID string) (*KeySet, error) {
+	s := &KeySet{path: path, activeID: activeID}
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewStaticKeySet returns a key set holding a single HS256 secret.
+func NewStaticKeySet(secret string) *KeySet {
+	k := &Key{Algorithm: AlgHS256, Secret: []byte(secret)}
+	return &KeySet{keys: map[string]*Key{"": k}, active: k}
+}
+
+// Reload reads the JWKS file again and swaps the keys atomically.
+func (s *KeySet) Reload() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	var set jwks
+	if err := json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("parse jwks %s: %w", s.path, err)
+	}
+	keys := make(map[string]*Key, len(set.Keys))
+	var active *Key
+	for _, raw := range set.Keys {
+		k, err := raw.toKey()
+		if err != nil {
+			return fmt.Errorf("parse jwks %s kid %q: %w", s.path, raw.Kid, err)
+		}
+		keys[k.ID] = k
+		if !k.canSign() {
+			continue
+		}
+		if (s.activeID != "" && k.ID == s.activeID) || (s.activeID == "" && active == nil) {
+			active = k
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("jwks %s contains no keys", s.path)
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.active = active
+	s.mu.Unlock()
+	return nil
+}
+
+// Lookup returns the key by kid.
+func (s *KeySet) Lookup(kid string) (*Key, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	k, ok := s.keys[kid]
+	if !ok && kid == "" && len(s.keys) == 1 {
+		for _, v := range s.keys {
+			return v, true
+		}
+	}
+	return k, ok
+}
+
+// Active returns the signing key.
+func (s *KeySet) Active() (*Key, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.active == nil {
+		return nil, errors.New("jwks has no signing key")
+	}
+	return s.active, nil
+}
+
+// Watch reloads the key set whenever the JWKS file changes until ctx is done.
+// The parent directory is watched so that atomic renames are picked up as well.
+func (s *KeySet) Watch(ctx context.Context) error {
+	if s.path == "" {
+		return nil
+	}
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := fw.Add(filepath.Dir(s.path)); err != nil {
+		fw.Close()
+		return err
+	}
+	go func() {
+		defer fw.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event := <-fw.Events:
+				if filepath.Clean(event.Name) != filepath.Clean(s.path) || event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) == 0 {
+					continue
+				}
+				if err := s.Reload(); err != nil {
+					zapx.Warnf("reload jwks %s failed, keep previous keys: %v", s.path, err)
+				}
+			case err := <-fw.Errors:
+				zapx.Errorf("watch jwks %s: %v", s.path, err)
+			}
+		}
+	}()
+	return nil
+}
+
+func (j jwk) toKey() (*Key, error) {
+	switch j.Kty {
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(j.K)
+		if err != nil {
+			return nil, err
+		}
+		return &Key{ID: j.Kid, Algorithm: AlgHS256, Secret: secret}, nil
+	case "RSA":
+		n, err := decodeBigInt(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(j.E)
+		if err != nil {
+			return nil, err
+		}
+		pub := &rsa.PublicKey{N: n, E: int(e.Int64())}
+		k := &Key{ID: j.Kid, Algorithm: AlgRS256, PublicKey: pub}
+		if j.D == "" {
+			return k, nil
+		}
+		d, err := decodeBigInt(j.D)
+		if err != nil {
+			return nil, err
+		}
+		p, err := decodeBigInt(j.P)
+		if err != nil {
+			return nil, err
+		}
+		q, err := decodeBigInt(j.Q)
+		if err != nil {
+			return nil, err
+		}
+		priv := &rsa.PrivateKey{PublicKey: *pub, D: d, Primes: []*big.Int{p, q}}
+		if err := priv.Validate(); err != nil {
+			return nil, err
+		}
+		priv.Precompute()
+		k.PrivateKey = priv
+		return k, nil
+	default:
+		return nil, fmt.Errorf("unsupported kty %q", j.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+	"github.com/kweaver-ai/idrm-go-frame/core/transport/rest/ginx"
+
+	"github.com/gin-gonic/gin"
+)
+
+const bearerPrefix = "Bearer "
+
+// MiddlewareOption is an auth middleware option.
+type MiddlewareOption func(*middlewareOptions)
+
+type middlewareOptions struct {
+	skipPaths map[string]bool
+	optional  bool
+}
+
+// SkipPaths requests on these paths are not authenticated.
+func SkipPaths(paths ...string) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		for _, p := range paths {
+			o.skipPaths[p] = true
+		}
+	}
+}
+
+// Optional lets requests without a token through, claims are only set when a valid token is present.
+func Optional() MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.optional = true
+	}
+}
+
+// Middleware verifies the bearer token and puts the claims into the context.
+func Middleware(m *Manager, opts ...MiddlewareOption) gin.HandlerFunc {
+	o := &middlewareOptions{skipPaths: map[string]bool{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return func(c *gin.Context) {
+		if o.skipPaths[c.FullPath()] || o.skipPaths[c.Request.URL.Path] {
+			c.Next()
+			return
+		}
+
+		token := BearerToken(c.Request)
+		if token == "" {
+			if o.optional {
+				c.Next()
+				return
+			}
+			ginx.AbortResponseWithCode(c, http.StatusUnauthorized, agerrors.NewCode(agcodes.CodeNotAuthorized, "missing bearer token"))
+			return
+		}
+
+		claims, err := m.Parse(token)
+		if err != nil {
+			ginx.AbortResponseWithCode(c, http.StatusUnauthorized, agerrors.NewCode(agcodes.WithCode(agcodes.CodeNotAuthorized, err.Error())))
+			return
+		}
+
+		c.Set(ClaimsKey, claims)
+		c.Set(TokenKey, token)
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), claims, token))
+		c.Next()
+	}
+}
+
+// BearerToken extracts the token from the Authorization header.
+func BearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	if len(h) > len(bearerPrefix) && strings.EqualFold(h[:len(bearerPrefix)], bearerPrefix) {
+		return strings.TrimSpace(h[len(bearerPrefix):])
+	}
+	return ""
+}
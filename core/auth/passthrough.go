@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+)
+
+// passthroughTransport forwards the caller's bearer token to downstream services.
+type passthroughTransport struct {
+	base http.RoundTripper
+}
+
+// NewPassthroughTransport wraps base so that requests built with a context
+// carrying a verified token get the same Authorization header, unless the
+// request sets one explicitly. Use it as the Transport of the *http.Client
+// given to httpclient.NewMiddlewareHTTPClient.
+func NewPassthroughTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &passthroughTransport{base: base}
+}
+
+func (t *passthroughTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "" {
+		return t.base.RoundTrip(req)
+	}
+	token, ok := TokenFromContext(req.Context())
+	if !ok {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", bearerPrefix+token)
+	return t.base.RoundTrip(req)
+}
+
+// Headers adds the caller's Authorization header to the headers map passed to httpclient.HTTPClient.
+func Headers(ctx context.Context, headers map[string]string) map[string]string {
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	if _, ok := headers["Authorization"]; ok {
+		return headers
+	}
+	if token, ok := TokenFromContext(ctx); ok {
+		headers["Authorization"] = bearerPrefix + token
+	}
+	return headers
+}
@@ -0,0 +1,229 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+)
+
+// DefaultHistorySize number of change sets kept by the Auditor.
+const DefaultHistorySize = 100
+
+// Masked replaces the values of the secrets in the change sets.
+const Masked = "******"
+
+// Kinds of Change.
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Change a changed leaf of the config.
+type Change struct {
+	Path string      `json:"path"`
+	Kind string      `json:"kind"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+%s: %v", c.Path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("-%s: %v", c.Path, c.Old)
+	}
+	return fmt.Sprintf("~%s: %v -> %v", c.Path, c.Old, c.New)
+}
+
+// ChangeSet the changes of a reload.
+type ChangeSet struct {
+	// Source keys of the reloaded KeyValues: file names, urls...
+	Source string `json:"source"`
+	// Version of the config, incremented on each reload changing it
+	Version uint64    `json:"version"`
+	Time    time.Time `json:"time"`
+	Changes []Change  `json:"changes"`
+}
+
+// ChangeListener is called with the change set of every reload changing the config.
+type ChangeListener func(ChangeSet)
+
+// Auditor records the changes of a Config: logged, published to the listeners and kept in a bounded history.
+type Auditor struct {
+	mu        sync.Mutex
+	size      int
+	version   uint64
+	history   []ChangeSet
+	listeners []ChangeListener
+}
+
+func newAuditor(size int) *Auditor {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &Auditor{size: size}
+}
+
+// Audit returns the Auditor of the config installed by Init.
+func Audit() *Auditor {
+	return AuditOf(manager.Config)
+}
+
+// AuditOf returns the Auditor of conf, nil when conf was not created by New.
+func AuditOf(conf Config) *Auditor {
+	if c, ok := conf.(*config); ok {
+		return c.audit
+	}
+	return nil
+}
+
+// OnChange registers fn, called synchronously by the watcher of the source.
+func (a *Auditor) OnChange(fn ChangeListener) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.listeners = append(a.listeners, fn)
+}
+
+// History returns the last change sets, oldest first.
+func (a *Auditor) History() []ChangeSet {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]ChangeSet{}, a.history...)
+}
+
+// Handler serves the history as json, mount it on the admin port:
+//
+//	engine.GET("/debug/config/history", gin.WrapH(config.Audit().Handler()))
+//
+// ?limit=n returns the n last change sets.
+func (a *Auditor) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		history := a.History()
+		if n, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && n >= 0 && n < len(history) {
+			history = history[len(history)-n:]
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"history": history})
+	})
+}
+
+func (a *Auditor) record(kvs []*sources.KeyValue, changes []Change) {
+	if len(changes) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(kvs))
+	for _, kv := range kvs {
+		keys = append(keys, kv.Key)
+	}
+	a.mu.Lock()
+	a.version++
+	cs := ChangeSet{Source: strings.Join(keys, ","), Version: a.version, Time: time.Now(), Changes: changes}
+	a.history = append(a.history, cs)
+	if len(a.history) > a.size {
+		a.history = append(a.history[:0:0], a.history[len(a.history)-a.size:]...)
+	}
+	listeners := append([]ChangeListener{}, a.listeners...)
+	a.mu.Unlock()
+
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = c.String()
+	}
+	zapx.Infof("config: version %d from %s, %d change(s): %s", cs.Version, cs.Source, len(changes), strings.Join(lines, "; "))
+	for _, fn := range listeners {
+		fn(cs)
+	}
+}
+
+// diff compares two config trees, the values of the secrets are masked.
+func diff(old, new map[string]interface{}, secret func(path string) bool) []Change {
+	var (
+		changes []Change
+		walk    func(prefix string, old, new map[string]interface{})
+		mask    func(path string, v interface{}) interface{}
+	)
+	mask = func(path string, v interface{}) interface{} {
+		if secret(path) {
+			return Masked
+		}
+		switch vt := v.(type) {
+		case map[string]interface{}:
+			masked := make(map[string]interface{}, len(vt))
+			for k, item := range vt {
+				masked[k] = mask(joinPath(path, k), item)
+			}
+			return masked
+		case []interface{}:
+			masked := make([]interface{}, len(vt))
+			for i, item := range vt {
+				masked[i] = mask(fmt.Sprintf("%s[%d]", path, i), item)
+			}
+			return masked
+		}
+		return v
+	}
+	walk = func(prefix string, old, new map[string]interface{}) {
+		for k, ov := range old {
+			path := joinPath(prefix, k)
+			nv, ok := new[k]
+			if !ok {
+				changes = append(changes, Change{Path: path, Kind: ChangeRemoved, Old: mask(path, ov)})
+				continue
+			}
+			om, oIsMap := ov.(map[string]interface{})
+			nm, nIsMap := nv.(map[string]interface{})
+			if oIsMap && nIsMap {
+				walk(path, om, nm)
+				continue
+			}
+			if !reflect.DeepEqual(ov, nv) {
+				changes = append(changes, Change{Path: path, Kind: ChangeChanged, Old: mask(path, ov), New: mask(path, nv)})
+			}
+		}
+		for k, nv := range new {
+			if _, ok := old[k]; !ok {
+				path := joinPath(prefix, k)
+				changes = append(changes, Change{Path: path, Kind: ChangeAdded, New: mask(path, nv)})
+			}
+		}
+	}
+	walk("", old, new)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// sensitiveKey reports whether a segment of path names a credential, the values under
+// credentials or secrets are masked whatever their key.
+func sensitiveKey(path string) bool {
+	for _, name := range strings.FieldsFunc(path, func(r rune) bool { return r == '.' || r == '[' || r == ']' }) {
+		if sensitiveName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sensitiveName(name string) bool {
+	name = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+	switch name {
+	case "pass", "pwd", "passwd":
+		return true
+	}
+	for _, s := range []string{"password", "secret", "token", "credential", "privatekey", "accesskey"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
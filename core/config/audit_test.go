@@ -0,0 +1,108 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAudit(t *testing.T) {
+	src := &testMemSource{data: make(chan string), current: `{"db":{"host":"a","password":"p1","timeout":3},"list":[1]}`}
+	c := New(WithSource(src), WithHistorySize(2))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	audit := AuditOf(c)
+	sets := make(chan ChangeSet, 10)
+	audit.OnChange(func(cs ChangeSet) { sets <- cs })
+	next := func() ChangeSet {
+		select {
+		case cs := <-sets:
+			return cs
+		case <-time.After(time.Second):
+			t.Fatal("no change set")
+		}
+		return ChangeSet{}
+	}
+
+	src.data <- `{"db":{"host":"a","password":"p2","timeout":5,"port":3306},"list":[1,2]}`
+	cs := next()
+	want := []Change{
+		{Path: "db.password", Kind: ChangeChanged, Old: Masked, New: Masked},
+		{Path: "db.port", Kind: ChangeAdded, New: float64(3306)},
+		{Path: "db.timeout", Kind: ChangeChanged, Old: float64(3), New: float64(5)},
+		{Path: "list", Kind: ChangeChanged, Old: []interface{}{float64(1)}, New: []interface{}{float64(1), float64(2)}},
+	}
+	if !reflect.DeepEqual(want, cs.Changes) || cs.Version != 1 || cs.Source != "mem" {
+		t.Fatalf("unexpected change set %+v", cs)
+	}
+
+	// a reload without change is not recorded
+	src.data <- `{"db":{"host":"a","password":"p2","timeout":5,"port":3306},"list":[1,2]}`
+	src.data <- `{"db":{"host":"b"}}`
+	if cs := next(); cs.Version != 2 || len(cs.Changes) != 1 || cs.Changes[0].String() != "~db.host: a -> b" {
+		t.Fatalf("unexpected change set %+v", cs)
+	}
+	src.data <- `{"db":{"host":"c"}}`
+	next()
+
+	history := audit.History()
+	if len(history) != 2 || history[0].Version != 2 || history[1].Version != 3 {
+		t.Fatalf("unexpected history %+v", history)
+	}
+
+	w := httptest.NewRecorder()
+	audit.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/debug/config/history?limit=1", nil))
+	var body struct {
+		History []ChangeSet `json:"history"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.History) != 1 || body.History[0].Version != 3 {
+		t.Fatalf("unexpected body %s", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "p2") {
+		t.Fatal("secret leaked")
+	}
+}
+
+func TestDiffMasksSecrets(t *testing.T) {
+	secret := func(path string) bool { return sensitiveKey(path) || path == "dsn" }
+	changes := diff(
+		map[string]interface{}{"dsn": "root:pw@db", "kafka": map[string]interface{}{"user": "u", "sasl_password": "x"}},
+		map[string]interface{}{"dsn": "root:pw2@db", "redis": map[string]interface{}{"Pass": "y", "addr": "r"}},
+		secret,
+	)
+	want := []Change{
+		{Path: "dsn", Kind: ChangeChanged, Old: Masked, New: Masked},
+		{Path: "kafka", Kind: ChangeRemoved, Old: map[string]interface{}{"user": "u", "sasl_password": Masked}},
+		{Path: "redis", Kind: ChangeAdded, New: map[string]interface{}{"Pass": Masked, "addr": "r"}},
+	}
+	if !reflect.DeepEqual(want, changes) {
+		t.Fatalf("want %+v, got %+v", want, changes)
+	}
+}
+
+func TestSensitiveKey(t *testing.T) {
+	for path, want := range map[string]bool{
+		"db.password":          true,
+		"credentials.key":      true,
+		"secrets.x":            true,
+		"oauth.Client-Secret":  true,
+		"auth.tokens[0].value": true,
+		"redis.pass":           true,
+		"redis.addr":           false,
+		"passenger.name":       false,
+		"tls.keyfile":          false,
+	} {
+		if got := sensitiveKey(path); got != want {
+			t.Errorf("%s: want %v, got %v", path, want, got)
+		}
+	}
+}
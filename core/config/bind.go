@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+)
+
+// ErrNotBindable the Config does not report the changes of its sources.
+var ErrNotBindable = errors.New("config does not support bind")
+
+// Validator is implemented by bound config types checking more than the tag options.
+type Validator interface {
+	Validate() error
+}
+
+type bindable interface {
+	Config
+	subscribe(func()) func()
+	// value reads key bypassing the cache of Value
+	value(key string) Value
+}
+
+// Binding a typed snapshot of a config key, scanned again on every change of the sources.
+// A new config failing to scan or to validate is logged and ignored, Load keeps returning the last good one.
+type Binding[T any] struct {
+	key     string
+	conf    bindable
+	current atomic.Pointer[T]
+	stop    func()
+	// reloading serializes the reloads of concurrent source watchers
+	reloading sync.Mutex
+
+	mu          sync.Mutex
+	subscribers []func(old, new T)
+}
+
+// Bind binds key of the config installed by Init, key "" binds the whole config:
+//
+//	db, err := config.Bind[gormx.Options]("data.db")
+//	db.OnChange(func(old, new gormx.Options) { ... })
+//	opts := db.Load()
+func Bind[T any](key string) (*Binding[T], error) {
+	return BindConfig[T](manager.Config, key)
+}
+
+// BindConfig binds key of conf, see Bind.
+func BindConfig[T any](conf Config, key string) (*Binding[T], error) {
+	bc, ok := conf.(bindable)
+	if !ok {
+		return nil, ErrNotBindable
+	}
+	b := &Binding[T]{key: key, conf: bc}
+	v, err := b.scan()
+	if err != nil {
+		return nil, err
+	}
+	b.current.Store(v)
+	b.stop = bc.subscribe(b.reload)
+	return b, nil
+}
+
+// Key returns the bound key.
+func (b *Binding[T]) Key() string {
+	return b.key
+}
+
+// Load returns the current snapshot, it must not be modified.
+func (b *Binding[T]) Load() T {
+	return *b.current.Load()
+}
+
+// OnChange registers fn called with the previous and the new snapshot after each accepted change.
+func (b *Binding[T]) OnChange(fn func(old, new T)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, fn)
+}
+
+// Close stops following the config, Load keeps returning the last snapshot.
+func (b *Binding[T]) Close() {
+	b.stop()
+}
+
+func (b *Binding[T]) scan() (*T, error) {
+	v := new(T)
+	var err error
+	if b.key == "" {
+		err = b.conf.Scan(v)
+	} else {
+		err = b.conf.value(b.key).Scan(v)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if val, ok := interface{}(v).(Validator); ok {
+		err = val.Validate()
+	}
+	return v, err
+}
+
+func (b *Binding[T]) reload() {
+	b.reloading.Lock()
+	defer b.reloading.Unlock()
+	next, err := b.scan()
+	if err != nil {
+		zapx.Errorf("config: reject invalid config %q, keep the last one: %v", b.key, err)
+		return
+	}
+	old := b.current.Load()
+	if reflect.DeepEqual(old, next) {
+		return
+	}
+	b.current.Store(next)
+	zapx.Infof("config: %q reloaded", b.key)
+	b.mu.Lock()
+	subscribers := append([]func(old, new T){}, b.subscribers...)
+	b.mu.Unlock()
+	for _, fn := range subscribers {
+		fn(*old, *next)
+	}
+}
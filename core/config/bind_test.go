@@ -0,0 +1,121 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+)
+
+// testMemSource a json source whose content is replaced by sending to data.
+type testMemSource struct {
+	data    chan string
+	current string
+}
+
+func (s *testMemSource) Load() ([]*sources.KeyValue, error) {
+	return []*sources.KeyValue{{Key: "mem", Value: []byte(s.current), Format: "json"}}, nil
+}
+
+func (s *testMemSource) Watch() (sources.Watcher, error) { return s, nil }
+
+func (s *testMemSource) Next() ([]*sources.KeyValue, error) {
+	data, ok := <-s.data
+	if !ok {
+		return nil, context.Canceled
+	}
+	s.current = data
+	return s.Load()
+}
+
+func (s *testMemSource) Stop() error {
+	close(s.data)
+	return nil
+}
+
+type testPoolConf struct {
+	Size int    `json:"size,default=10,range=[1:100]"`
+	Mode string `json:"mode,optional"`
+}
+
+func (c testPoolConf) Validate() error {
+	if c.Mode == "forbidden" {
+		return errors.New("forbidden mode")
+	}
+	return nil
+}
+
+func TestBind(t *testing.T) {
+	src := &testMemSource{data: make(chan string), current: `{"pool":{"size":5}}`}
+	c := New(WithSource(src))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	b, err := BindConfig[testPoolConf](c, "pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.Load(); got.Size != 5 {
+		t.Fatalf("want size 5, got %+v", got)
+	}
+
+	changes := make(chan [2]testPoolConf, 10)
+	b.OnChange(func(old, new testPoolConf) { changes <- [2]testPoolConf{old, new} })
+	second := make(chan testPoolConf, 10)
+	b.OnChange(func(_, new testPoolConf) { second <- new })
+
+	next := func() [2]testPoolConf {
+		select {
+		case c := <-changes:
+			return c
+		case <-time.After(time.Second):
+			t.Fatal("no change")
+		}
+		return [2]testPoolConf{}
+	}
+
+	src.data <- `{"pool":{"size":20,"mode":"fast"}}`
+	if got := next(); got[0].Size != 5 || got[1].Size != 20 || got[1].Mode != "fast" {
+		t.Fatalf("unexpected change %+v", got)
+	}
+	if got := <-second; got.Size != 20 {
+		t.Fatalf("unexpected change %+v", got)
+	}
+
+	// rejected by the range and by Validate, the last good snapshot is kept
+	src.data <- `{"pool":{"size":500,"mode":"fast"}}`
+	src.data <- `{"pool":{"size":30,"mode":"forbidden"}}`
+	// unrelated changes do not notify
+	src.data <- `{"pool":{"size":20,"mode":"fast"},"other":1}`
+	src.data <- `{"pool":{"size":40,"mode":"fast"}}`
+	if got := next(); got[0].Size != 20 || got[1].Size != 40 {
+		t.Fatalf("unexpected change %+v", got)
+	}
+	if got := b.Load(); got.Size != 40 {
+		t.Fatalf("want size 40, got %+v", got)
+	}
+
+	b.Close()
+	src.data <- `{"pool":{"size":50}}`
+	src.data <- `{"pool":{"size":60}}`
+	if got := b.Load(); got.Size != 40 {
+		t.Fatalf("closed binding changed: %+v", got)
+	}
+}
+
+func TestBindInvalid(t *testing.T) {
+	c := New(WithSource(newTestJSONSource(`{"pool":{"size":0}}`)))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BindConfig[testPoolConf](c, "pool"); err == nil {
+		t.Fatal("want an error")
+	}
+	if _, err := BindConfig[testPoolConf](c, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("want ErrNotFound, got %v", err)
+	}
+}
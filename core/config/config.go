@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	stdjson "encoding/json"
 	"errors"
 	"reflect"
 	"sync"
@@ -10,9 +11,14 @@ import (
 	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
 
 	// init encoding
+	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/ini"
 	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/json"
+	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/properties"
+	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/toml"
 	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/yaml"
 	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+
+	"google.golang.org/protobuf/proto"
 )
 
 var (
@@ -42,6 +48,12 @@ type config struct {
 	cached    sync.Map
 	observers sync.Map
 	watchers  []sources.Watcher
+	secrets   *secretResolver
+	audit     *Auditor
+
+	listenerMu sync.Mutex
+	listenerID uint64
+	listeners  map[uint64]func()
 }
 
 // New ,new a config with options.
@@ -53,9 +65,16 @@ func New(opts ...Option) Config {
 	for _, opt := range opts {
 		opt(&o)
 	}
+	var secrets *secretResolver
+	if len(o.secrets) > 0 {
+		secrets = newSecretResolver(o.secrets)
+		o.resolver = secrets.chain(o.resolver)
+	}
 	return &config{
-		opts:   o,
-		reader: newReader(o),
+		opts:    o,
+		reader:  newReader(o),
+		secrets: secrets,
+		audit:   newAuditor(o.historySize),
 	}
 }
 
@@ -72,14 +91,21 @@ func (c *config) watch(w sources.Watcher) {
 			zapx.Errorf("failed to watch next config: %v", err)
 			continue
 		}
+		before, _ := c.reader.Source()
 		if err := c.reader.Merge(kvs...); err != nil {
 			zapx.Errorf("failed to merge next config: %v", err)
 			continue
 		}
+		if c.opts.strict {
+			if err := c.checkStrict(); err != nil {
+				zapx.Warnf("next config: %v", err)
+			}
+		}
 		if err := c.reader.Resolve(); err != nil {
 			zapx.Errorf("failed to resolve next config: %v", err)
 			continue
 		}
+		c.recordChanges(kvs, before)
 		c.cached.Range(func(key, value interface{}) bool {
 			k := key.(string)
 			v := value.(Value)
@@ -91,6 +117,51 @@ func (c *config) watch(w sources.Watcher) {
 			}
 			return true
 		})
+		c.notify()
+	}
+}
+
+// recordChanges audits the difference between before, the json of the values before the merge, and the values.
+func (c *config) recordChanges(kvs []*sources.KeyValue, before []byte) {
+	after, err := c.reader.Source()
+	if err != nil {
+		return
+	}
+	var old, cur map[string]interface{}
+	if stdjson.Unmarshal(before, &old) != nil || stdjson.Unmarshal(after, &cur) != nil {
+		return
+	}
+	c.audit.record(kvs, diff(old, cur, func(path string) bool {
+		return sensitiveKey(path) || c.secrets.isSecret(path)
+	}))
+}
+
+// subscribe registers fn called after every change of the sources, the returned func removes it.
+func (c *config) subscribe(fn func()) func() {
+	c.listenerMu.Lock()
+	defer c.listenerMu.Unlock()
+	if c.listeners == nil {
+		c.listeners = make(map[uint64]func())
+	}
+	c.listenerID++
+	id := c.listenerID
+	c.listeners[id] = fn
+	return func() {
+		c.listenerMu.Lock()
+		defer c.listenerMu.Unlock()
+		delete(c.listeners, id)
+	}
+}
+
+func (c *config) notify() {
+	c.listenerMu.Lock()
+	fns := make([]func(), 0, len(c.listeners))
+	for _, fn := range c.listeners {
+		fns = append(fns, fn)
+	}
+	c.listenerMu.Unlock()
+	for _, fn := range fns {
+		fn()
 	}
 }
 
@@ -100,8 +171,11 @@ func (c *config) Load() error {
 		if err != nil {
 			return err
 		}
+		// the sources are logged before Resolve, secrets are still references
 		for _, v := range kvs {
-			if len(v.Value) <= 1024 {
+			if v.Sensitive {
+				zapx.Debugf("config loaded: %s format: %v, size: %d", v.Key, v.Format, len(v.Value))
+			} else if len(v.Value) <= 1024 {
 				zapx.Debugf("config loaded: %s format: %v, values: %s", v.Key, v.Format, v.Value)
 			} else {
 				zapx.Debugf("config loaded: %s format: %v, values: too long", v.Key, v.Format)
@@ -119,6 +193,12 @@ func (c *config) Load() error {
 		c.watchers = append(c.watchers, w)
 		go c.watch(w)
 	}
+	if c.opts.strict {
+		if err := c.checkStrict(); err != nil {
+			zapx.Errorf("strict config: %v", err)
+			return err
+		}
+	}
 	if err := c.reader.Resolve(); err != nil {
 		zapx.Errorf("failed to resolve config source: %v", err)
 		return err
@@ -126,6 +206,39 @@ func (c *config) Load() error {
 	return nil
 }
 
+// checkStrict returns the keys unknown to the registered schema, except the ones set by the environment.
+func (c *config) checkStrict() error {
+	s := RegisteredSchema()
+	if s == nil {
+		return errors.New("config: strict mode without schema, call RegisterSchema")
+	}
+	values, err := sourceMap(c.reader)
+	if err != nil {
+		return err
+	}
+	var unknown []LintIssue
+	for _, issue := range Lint(s, values) {
+		if issue.Kind == LintUnknown && !c.fromEnv(issue.Path) {
+			unknown = append(unknown, issue)
+		}
+	}
+	if len(unknown) > 0 {
+		return &LintError{Issues: unknown}
+	}
+	return nil
+}
+
+// fromEnv reports whether every value under path comes from the environment variables.
+func (c *config) fromEnv(path string) bool {
+	origins := c.reader.Explain(path)
+	for _, o := range origins {
+		if o.Priority != sources.PriorityEnv {
+			return false
+		}
+	}
+	return len(origins) > 0
+}
+
 func (c *config) Value(key string) Value {
 	if v, ok := c.cached.Load(key); ok {
 		return v.(Value)
@@ -137,12 +250,22 @@ func (c *config) Value(key string) Value {
 	return &errValue{err: ErrNotFound}
 }
 
+func (c *config) value(key string) Value {
+	if v, ok := c.reader.Value(key); ok {
+		return v
+	}
+	return &errValue{err: ErrNotFound}
+}
+
 func (c *config) Scan(v interface{}) error {
 	data, err := c.reader.Source()
 	if err != nil {
 		return err
 	}
-	return unmarshalJSON(data, v)
+	if _, ok := v.(proto.Message); ok {
+		return unmarshalJSON(data, v)
+	}
+	return scanJSON(data, v, "")
 }
 
 func (c *config) Watch(key string, o Observer) error {
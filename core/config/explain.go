@@ -0,0 +1,39 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+)
+
+// Origin the source supplying the effective value of a config path.
+type Origin struct {
+	// Path dotted path of the leaf value
+	Path string
+	// Source key of the KeyValue: the file name, the env variable or the flag
+	Source   string
+	Format   string
+	Priority int
+}
+
+func (o Origin) String() string {
+	return fmt.Sprintf("%s from %s %s", o.Path, sources.PriorityName(o.Priority), o.Source)
+}
+
+// Explain reports the sources of the effective values of key in the config installed by Init,
+// one Origin per leaf value under key:
+//
+//	for _, o := range config.Explain("db") {
+//		fmt.Println(o) // db.host from flag --db.host
+//	}
+func Explain(key string) []Origin {
+	return ExplainConfig(manager.Config, key)
+}
+
+// ExplainConfig reports the sources of key in conf, see Explain.
+func ExplainConfig(conf Config, key string) []Origin {
+	if c, ok := conf.(*config); ok {
+		return c.reader.Explain(key)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+)
+
+type testKVSource []*sources.KeyValue
+
+func (s testKVSource) Load() ([]*sources.KeyValue, error) { return s, nil }
+
+func (s testKVSource) Watch() (sources.Watcher, error) {
+	return newTestWatcher(make(chan struct{}), make(chan struct{})), nil
+}
+
+func TestPriority(t *testing.T) {
+	// flags and env are loaded before the file, they still win
+	c := New(WithSource(
+		testKVSource{{Key: "--db.host", Value: []byte(`{"db":{"host":"flag"}}`), Format: "json", Priority: sources.PriorityFlag}},
+		testKVSource{{Key: "db.port", Value: []byte("env"), Priority: sources.PriorityEnv}},
+		testKVSource{{Key: "config.json", Value: []byte(`{"db":{"host":"file","port":1,"name":"file"},"log":"file"}`), Format: "json"}},
+		testKVSource{{Key: "defaults", Value: []byte(`{"db":{"name":"default","user":"default"}}`), Format: "json", Priority: sources.PriorityDefault}},
+	))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	var conf struct {
+		DB  map[string]string `json:"db"`
+		Log string            `json:"log"`
+	}
+	if err := c.Scan(&conf); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"host": "flag", "port": "env", "name": "file", "user": "default"}
+	if !reflect.DeepEqual(want, conf.DB) {
+		t.Fatalf("want %v, got %v", want, conf.DB)
+	}
+
+	got := ExplainConfig(c, "db")
+	wantOrigins := []Origin{
+		{Path: "db.host", Source: "--db.host", Format: "json", Priority: sources.PriorityFlag},
+		{Path: "db.name", Source: "config.json", Format: "json", Priority: sources.PriorityFile},
+		{Path: "db.port", Source: "db.port", Priority: sources.PriorityEnv},
+		{Path: "db.user", Source: "defaults", Format: "json", Priority: sources.PriorityDefault},
+	}
+	if !reflect.DeepEqual(wantOrigins, got) {
+		t.Fatalf("want %v, got %v", wantOrigins, got)
+	}
+	if s := ExplainConfig(c, "db.host")[0].String(); s != "db.host from flag --db.host" {
+		t.Fatalf("unexpected %s", s)
+	}
+	if len(ExplainConfig(c, "missing")) != 0 {
+		t.Fatal("unexpected origin of a missing key")
+	}
+}
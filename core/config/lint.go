@@ -0,0 +1,265 @@
+package config
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+)
+
+// Kinds of LintIssue.
+const (
+	LintUnknown     = "unknown"
+	LintType        = "type"
+	LintRequired    = "required"
+	LintValue       = "value"
+	LintPlaceholder = "placeholder"
+)
+
+var placeholderPattern = regexp.MustCompile(`\${(.*?)}`)
+
+// LintIssue a problem of a config tree found by Lint.
+type LintIssue struct {
+	Path    string `json:"path"`
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	path := i.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return path + ": " + i.Message
+}
+
+// LintError the issues of a config, returned by Load in strict mode.
+type LintError struct {
+	Issues []LintIssue
+}
+
+func (e *LintError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "config: %d issue(s)", len(e.Issues))
+	for _, i := range e.Issues {
+		sb.WriteString("\n\t")
+		sb.WriteString(i.String())
+	}
+	return sb.String()
+}
+
+// Lint checks the config tree values, before the placeholders are resolved, against s: unknown keys,
+// wrong types, missing required values, options and ranges, and the placeholders without default
+// referencing a missing key. The keys are matched case insensitively, as Scan does.
+func Lint(s *Schema, values map[string]interface{}) []LintIssue {
+	l := &linter{root: values}
+	if s != nil {
+		l.walk(s, values, "")
+	}
+	l.placeholders(values, "")
+	sort.SliceStable(l.issues, func(i, j int) bool { return l.issues[i].Path < l.issues[j].Path })
+	return l.issues
+}
+
+// LintSources merges the sources as Load does, without resolving the placeholders, and lints the result.
+func LintSources(s *Schema, srcs ...sources.Source) ([]LintIssue, error) {
+	o := options{decoder: defaultDecoder, resolver: func(map[string]interface{}) error { return nil }}
+	r := newReader(o)
+	for _, src := range srcs {
+		kvs, err := src.Load()
+		if err != nil {
+			return nil, err
+		}
+		if err := r.Merge(kvs...); err != nil {
+			return nil, err
+		}
+	}
+	values, err := sourceMap(r)
+	if err != nil {
+		return nil, err
+	}
+	return Lint(s, values), nil
+}
+
+func sourceMap(r Reader) (map[string]interface{}, error) {
+	data, err := r.Source()
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]interface{}
+	if err := stdjson.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+type linter struct {
+	root   map[string]interface{}
+	issues []LintIssue
+}
+
+func (l *linter) add(path, kind, format string, args ...interface{}) {
+	l.issues = append(l.issues, LintIssue{Path: path, Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *linter) walk(s *Schema, v interface{}, path string) {
+	if v == nil {
+		return
+	}
+	if str, ok := v.(string); ok && strings.Contains(str, "${") {
+		// the type is known once resolved
+		return
+	}
+	if len(s.Type) > 0 && !matchType(s.Type, v) {
+		l.add(path, LintType, "expected %s, got %s", strings.Join(s.Type, " or "), rawKind(v))
+		return
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			l.add(path, LintValue, "value %v is not one of %v", v, s.Enum)
+		}
+	}
+	if f, ok := number(v); ok && !inBounds(s, f) {
+		l.add(path, LintValue, "value %v out of range", f)
+	}
+
+	switch vt := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(vt))
+		for k := range vt {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key := joinPath(path, k)
+			if p, ok := lookupProperty(s.Properties, k); ok {
+				l.walk(p, vt[k], key)
+				continue
+			}
+			switch ap := s.AdditionalProperties.(type) {
+			case *Schema:
+				l.walk(ap, vt[k], key)
+			case bool:
+				if !ap {
+					l.add(key, LintUnknown, "unknown key")
+				}
+			}
+		}
+		for _, name := range s.Required {
+			if _, ok := lookupKey(vt, name); !ok {
+				l.add(joinPath(path, name), LintRequired, "is required")
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range vt {
+				l.walk(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	}
+}
+
+// placeholders reports the ${key} without default whose key is missing, ${secret:...} are left to the providers.
+func (l *linter) placeholders(v interface{}, path string) {
+	switch vt := v.(type) {
+	case map[string]interface{}:
+		for k, item := range vt {
+			l.placeholders(item, joinPath(path, k))
+		}
+	case []interface{}:
+		for i, item := range vt {
+			l.placeholders(item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	case string:
+		for _, m := range placeholderPattern.FindAllStringSubmatch(vt, -1) {
+			name := strings.TrimSpace(m[1])
+			if strings.HasPrefix(name, "secret:") || strings.Contains(name, ":") {
+				continue
+			}
+			if _, ok := readValue(l.root, name); !ok {
+				l.add(path, LintPlaceholder, "%s is not resolved, %s is not set and has no default", m[0], name)
+			}
+		}
+	}
+}
+
+func lookupProperty(props map[string]*Schema, key string) (*Schema, bool) {
+	if p, ok := props[key]; ok {
+		return p, true
+	}
+	for name, p := range props {
+		if strings.EqualFold(name, key) {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func matchType(types SchemaType, v interface{}) bool {
+	for _, t := range types {
+		switch t {
+		case TypeObject:
+			if _, ok := v.(map[string]interface{}); ok {
+				return true
+			}
+		case TypeArray:
+			if _, ok := v.([]interface{}); ok {
+				return true
+			}
+		case TypeString:
+			if _, ok := v.(string); ok {
+				return true
+			}
+		case TypeBoolean:
+			if _, ok := v.(bool); ok {
+				return true
+			}
+			// the values of the untyped formats are converted by Scan
+			if str, ok := v.(string); ok {
+				if _, err := strconv.ParseBool(strings.TrimSpace(str)); err == nil {
+					return true
+				}
+			}
+		case TypeNumber:
+			if _, ok := number(v); ok {
+				return true
+			}
+		case TypeInteger:
+			if f, ok := number(v); ok && f == math.Trunc(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// number returns the value of a number, or of a string Scan converts to a number.
+func number(v interface{}) (float64, bool) {
+	switch vt := v.(type) {
+	case float64:
+		return vt, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(vt), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func inBounds(s *Schema, f float64) bool {
+	return (s.Minimum == nil || f >= *s.Minimum) &&
+		(s.Maximum == nil || f <= *s.Maximum) &&
+		(s.ExclusiveMinimum == nil || f > *s.ExclusiveMinimum) &&
+		(s.ExclusiveMaximum == nil || f < *s.ExclusiveMaximum)
+}
@@ -0,0 +1,158 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+)
+
+type testDBConf struct {
+	Driver  string        `json:",default=mysql,options=mysql|postgres"`
+	Host    string        `json:"host"`
+	Port    int           `json:"port,range=[1:65535]"`
+	Timeout time.Duration `json:",default=3s"`
+	Debug   bool          `json:",string,optional"`
+}
+
+type testLintConf struct {
+	Name   string            `json:"name,options=a|b"`
+	DB     testDBConf        `json:"db"`
+	Labels map[string]string `json:"labels"`
+	Hosts  []string          `json:"hosts"`
+	Cache  *testDBConf       `json:"cache"`
+	Any    interface{}       `json:"any"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	s := SchemaOf(testLintConf{})
+	data, err := json.Marshal(s.Properties["db"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"title":"testDBConf","type":"object","properties":{` +
+		`"Debug":{"type":"string"},` +
+		`"Driver":{"type":"string","enum":["mysql","postgres"],"default":"mysql"},` +
+		`"Timeout":{"type":["string","integer"],"format":"duration","default":"3s"},` +
+		`"host":{"type":"string"},` +
+		`"port":{"type":"integer","minimum":1,"maximum":65535}},` +
+		`"additionalProperties":false,"required":["port"]}`
+	if string(data) != want {
+		t.Fatalf("want %s\ngot  %s", want, data)
+	}
+	// db is required through db.port, the pointer cache is not
+	if !reflect.DeepEqual(s.Required, []string{"db", "name"}) {
+		t.Fatalf("unexpected required %v", s.Required)
+	}
+	if s.Schema != SchemaDraft || s.Properties["any"].Type != nil || s.Properties["labels"].AdditionalProperties.(*Schema).Type[0] != TypeString {
+		t.Fatalf("unexpected schema %+v", s)
+	}
+}
+
+func TestLint(t *testing.T) {
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+		"name": "c",
+		"db": {"driver": "oracle", "HOST": 1, "port": 70000, "timeout": "${TIMEOUT}", "debgu": true},
+		"labels": {"a": "x", "b": 2},
+		"hosts": ["h1", 2],
+		"any": {"whatever": 1},
+		"extra": "${db.driver} ${missing} ${missing:default} ${secret:file:x}"
+	}`), &values); err != nil {
+		t.Fatal(err)
+	}
+	got := Lint(SchemaOf(testLintConf{}), values)
+	want := []LintIssue{
+		{Path: "db.HOST", Kind: LintType, Message: "expected string, got a number"},
+		{Path: "db.debgu", Kind: LintUnknown, Message: "unknown key"},
+		{Path: "db.driver", Kind: LintValue, Message: "value oracle is not one of [mysql postgres]"},
+		{Path: "db.port", Kind: LintValue, Message: "value 70000 out of range"},
+		{Path: "db.timeout", Kind: LintPlaceholder, Message: "${TIMEOUT} is not resolved, TIMEOUT is not set and has no default"},
+		{Path: "extra", Kind: LintUnknown, Message: "unknown key"},
+		{Path: "extra", Kind: LintPlaceholder, Message: "${missing} is not resolved, missing is not set and has no default"},
+		{Path: "hosts[1]", Kind: LintType, Message: "expected string, got a number"},
+		{Path: "labels.b", Kind: LintType, Message: "expected string, got a number"},
+		{Path: "name", Kind: LintValue, Message: "value c is not one of [a b]"},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v\ngot  %v", want, got)
+	}
+
+	if got := Lint(SchemaOf(testLintConf{}), map[string]interface{}{"db": map[string]interface{}{}}); len(got) != 2 ||
+		got[0].String() != "db.port: is required" || got[1].String() != "name: is required" {
+		t.Fatalf("unexpected %v", got)
+	}
+	// the strings of properties and ini files are converted by Scan
+	strs := map[string]interface{}{"name": "a", "db": map[string]interface{}{"port": "3306", "Debug": "true"}, "cache": map[string]interface{}{"port": "x"}}
+	if got := Lint(SchemaOf(testLintConf{}), strs); len(got) != 1 || got[0].String() != "cache.port: expected integer, got a string" {
+		t.Fatalf("unexpected %v", got)
+	}
+	strs["db"].(map[string]interface{})["port"] = "70000"
+	if got := Lint(SchemaOf(testLintConf{}), strs); len(got) != 2 || got[1].String() != "db.port: value 70000 out of range" {
+		t.Fatalf("unexpected %v", got)
+	}
+}
+
+func TestStrict(t *testing.T) {
+	defer func(keys map[string][]reflect.Type) { schemas.keys = keys }(schemas.keys)
+	schemas.keys = make(map[string][]reflect.Type)
+
+	file := testKVSource{{Key: "config.json", Value: []byte(`{"db":{"host":"h","port":1,"hots":"x"}}`), Format: "json"}}
+	env := testKVSource{{Key: "HOME", Value: []byte("/root"), Priority: sources.PriorityEnv}}
+	if err := New(WithSource(file), WithStrict()).Load(); err == nil {
+		t.Fatal("want an error without schema")
+	}
+
+	RegisterSchema("db", testDBConf{})
+	err := New(WithSource(file, env), WithStrict()).Load()
+	var lintErr *LintError
+	if !errors.As(err, &lintErr) || len(lintErr.Issues) != 1 || lintErr.Issues[0].Path != "db.hots" {
+		t.Fatalf("want the unknown db.hots, got %v", err)
+	}
+	if err := New(WithSource(file, env)).Load(); err != nil {
+		t.Fatalf("the unknown keys are allowed by default: %v", err)
+	}
+
+	issues, err := LintSources(RegisteredSchema(), file)
+	if err != nil || len(issues) != 1 || issues[0].Kind != LintUnknown {
+		t.Fatalf("unexpected %v %v", issues, err)
+	}
+}
+
+func TestRegisteredSchemaNested(t *testing.T) {
+	defer func(keys map[string][]reflect.Type) { schemas.keys = keys }(schemas.keys)
+	schemas.keys = make(map[string][]reflect.Type)
+
+	type pool struct {
+		Size int `json:"size,optional"`
+	}
+	type app struct {
+		Name string `json:"name"`
+	}
+	RegisterSchema("", app{})
+	RegisterSchema("data.db", testDBConf{})
+	RegisterSchema("data.db", pool{})
+
+	s := RegisteredSchema()
+	var values map[string]interface{}
+	if err := json.Unmarshal([]byte(`{"name":"a","data":{"db":{"host":"h","port":1,"size":2}}}`), &values); err != nil {
+		t.Fatal(err)
+	}
+	if issues := Lint(s, values); len(issues) != 0 {
+		t.Fatalf("unexpected %v", issues)
+	}
+
+	if err := json.Unmarshal([]byte(`{"name":"a","data":{"db":{"host":"h","port":1,"sise":2}}}`), &values); err != nil {
+		t.Fatal(err)
+	}
+	if issues := Lint(s, values); len(issues) != 1 || issues[0].Path != "data.db.sise" || issues[0].Kind != LintUnknown {
+		t.Fatalf("want the unknown data.db.sise, got %v", issues)
+	}
+
+	if issues := Lint(s, map[string]interface{}{"name": "a"}); len(issues) != 1 || issues[0].Path != "data" || issues[0].Kind != LintRequired {
+		t.Fatalf("want the required data, got %v", issues)
+	}
+}
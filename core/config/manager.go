@@ -32,6 +32,15 @@ func Init(sources ...sources.Source) {
 	manager = &Manager{Config: c}
 }
 
+// InitWithOptions config with options, as WithStrict, the sources are set by WithSource.
+func InitWithOptions(opts ...Option) {
+	c := New(opts...)
+	if err := c.Load(); err != nil {
+		panic(err)
+	}
+	manager = &Manager{Config: c}
+}
+
 func Load() {
 	if err := manager.Config.Load(); err != nil {
 		panic(err)
@@ -22,6 +22,11 @@ type options struct {
 	sources  []sources.Source
 	decoder  Decoder
 	resolver Resolver
+	secrets  []SecretProvider
+	// historySize change sets kept by the Auditor
+	historySize int
+	// strict fails Load on the keys unknown to the registered schema
+	strict bool
 }
 
 // WithSource with config source.
@@ -42,6 +47,21 @@ func WithDecoder(d Decoder) Option {
 	}
 }
 
+// WithHistorySize sets the number of change sets kept by the Auditor, DefaultHistorySize by default.
+func WithHistorySize(n int) Option {
+	return func(o *options) {
+		o.historySize = n
+	}
+}
+
+// WithStrict makes Load fail on the keys unknown to the structs registered by RegisterSchema,
+// the keys loaded from the environment variables are ignored. A reload adding unknown keys is logged.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
 // WithResolver with config resolver.
 func WithResolver(r Resolver) Option {
 	return func(o *options) {
@@ -76,6 +96,10 @@ func defaultDecoder(src *sources.KeyValue, target map[string]interface{}) error
 // placeholder format in ${key:default}.
 func defaultResolver(input map[string]interface{}) error {
 	mapper := func(name string) string {
+		if strings.HasPrefix(strings.TrimSpace(name), "secret:") {
+			// left to the providers of WithSecrets
+			return "${" + name + "}"
+		}
 		args := strings.SplitN(strings.TrimSpace(name), ":", 2) //nolint:gomnd
 		if v, has := readValue(input, args[0]); has {
 			s, _ := v.String()
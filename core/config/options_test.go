@@ -1,11 +1,15 @@
 package config
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources/file"
 )
 
 func TestDefaultDecoder(t *testing.T) {
@@ -228,3 +232,59 @@ func TestExpand(t *testing.T) {
 		}
 	}
 }
+
+func TestFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.toml":       "[db]\nhost = \"toml\"\nport = 3306\npassword = \"123456\"\nversion = \"1.10\"\ndebug = true\n",
+		"app.ini":        "[db]\nhost = ini\nport = 3306\npassword = 123456\nversion = 1.10\ndebug = true\n",
+		"app.properties": "db.host=properties\ndb.port=3306\ndb.password=123456\ndb.version=1.10\ndb.debug=true\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		c := New(WithSource(file.NewSource(path)))
+		if err := c.Load(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var conf struct {
+			DB struct {
+				Host     string `json:"host"`
+				Port     int    `json:"port"`
+				Password string `json:"password"`
+				Version  string `json:"version"`
+				Debug    bool   `json:"debug"`
+			} `json:"db"`
+		}
+		if err := c.Scan(&conf); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if conf.DB.Host != strings.TrimPrefix(filepath.Ext(name), ".") || conf.DB.Port != 3306 ||
+			conf.DB.Password != "123456" || conf.DB.Version != "1.10" || !conf.DB.Debug {
+			t.Fatalf("%s: unexpected %+v", name, conf)
+		}
+		_ = c.Close()
+	}
+
+	// the strings that do not convert are reported
+	path := filepath.Join(dir, "bad.properties")
+	if err := os.WriteFile(path, []byte("db.port=abc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c := New(WithSource(file.NewSource(path)))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	var conf struct {
+		DB struct {
+			Port int `json:"port"`
+		} `json:"db"`
+	}
+	var scanErr *ScanError
+	if err := c.Scan(&conf); !errors.As(err, &scanErr) || scanErr.Fields[0].Path != "db.port" {
+		t.Fatalf("unexpected %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/gob"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 
@@ -23,46 +24,107 @@ type Reader interface {
 	Value(string) (Value, bool)
 	Source() ([]byte, error)
 	Resolve() error
+	Explain(string) []Origin
 }
 
 type reader struct {
 	opts   options
 	values map[string]interface{}
-	lock   sync.Mutex
+	// origins source of every leaf path of values
+	origins map[string]Origin
+	lock    sync.Mutex
 }
 
 func newReader(opts options) Reader {
 	return &reader{
-		opts:   opts,
-		values: make(map[string]interface{}),
-		lock:   sync.Mutex{},
+		opts:    opts,
+		values:  make(map[string]interface{}),
+		origins: make(map[string]Origin),
+		lock:    sync.Mutex{},
 	}
 }
 
+// Merge merges kvs into the values, leaves set by a source of a higher priority are kept.
 func (r *reader) Merge(kvs ...*sources.KeyValue) error {
 	r.lock.Lock()
+	defer r.lock.Unlock()
 	merged, err := cloneMap(r.values)
-	r.lock.Unlock()
 	if err != nil {
 		return err
 	}
+	origins := make(map[string]Origin, len(r.origins))
+	for k, v := range r.origins {
+		origins[k] = v
+	}
 	for _, kv := range kvs {
 		next := make(map[string]interface{})
 		if err := r.opts.decoder(kv, next); err != nil {
-			zapx.Errorf("Failed to config decode error: %v key: %s value: %s", err, kv.Key, string(kv.Value))
+			zapx.Errorf("Failed to config decode error: %v key: %s format: %s", err, kv.Key, kv.Format)
 			return err
 		}
-		if err := mergo.Map(&merged, convertMap(next), mergo.WithOverride); err != nil {
-			zapx.Errorf("Failed to config merge error: %v key: %s value: %s", err, kv.Key, string(kv.Value))
+		src := convertMap(next).(map[string]interface{})
+		claim(origins, "", src, Origin{Source: kv.Key, Format: kv.Format, Priority: kv.Priority})
+		if err := mergo.Map(&merged, src, mergo.WithOverride); err != nil {
+			zapx.Errorf("Failed to config merge error: %v key: %s format: %s", err, kv.Key, kv.Format)
 			return err
 		}
 	}
-	r.lock.Lock()
 	r.values = merged
-	r.lock.Unlock()
+	r.origins = origins
 	return nil
 }
 
+// claim removes from src the leaves owned by a source of a higher priority and records the origin of the others.
+func claim(origins map[string]Origin, prefix string, src map[string]interface{}, o Origin) {
+	for k, v := range src {
+		path := joinPath(prefix, k)
+		if sub, ok := v.(map[string]interface{}); ok {
+			// an empty object is merged into the existing one
+			if len(sub) > 0 {
+				claim(origins, path, sub, o)
+				if len(sub) == 0 {
+					delete(src, k)
+				}
+			}
+			continue
+		}
+		if !claimable(origins, path, o.Priority) {
+			delete(src, k)
+			continue
+		}
+		for p := range origins {
+			if p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(path, p+".") {
+				delete(origins, p)
+			}
+		}
+		o.Path = path
+		origins[path] = o
+	}
+}
+
+// claimable reports whether no source of a higher priority owns path, one of its parents or one of its children.
+func claimable(origins map[string]Origin, path string, priority int) bool {
+	for p, o := range origins {
+		if o.Priority > priority && (p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(path, p+".")) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *reader) Explain(path string) []Origin {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var res []Origin
+	for p, o := range r.origins {
+		if path == "" || p == path || strings.HasPrefix(p, path+".") || strings.HasPrefix(path, p+".") {
+			res = append(res, o)
+		}
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Path < res[j].Path })
+	return res
+}
+
 func (r *reader) Value(path string) (Value, bool) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
@@ -142,7 +204,7 @@ func readValue(values map[string]interface{}, path string) (Value, bool) {
 			return nil, false
 		}
 		if idx == last {
-			av := &atomicValue{}
+			av := &atomicValue{path: path}
 			av.Store(value)
 			return av, true
 		}
@@ -0,0 +1,584 @@
+package config
+
+import (
+	"encoding"
+	stdjson "encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError an invalid field found by Scan, Path is the dotted config key of the field.
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ScanError every invalid field found by Scan.
+type ScanError struct {
+	Fields []*FieldError
+}
+
+func (e *ScanError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "config: %d invalid field(s)", len(e.Fields))
+	for _, f := range e.Fields {
+		sb.WriteString("\n\t")
+		sb.WriteString(f.Error())
+	}
+	return sb.String()
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	jsonUnmarshalerType = reflect.TypeOf((*stdjson.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// scanJSON unmarshals data into v then applies the go-zero style options of its json tags:
+//
+//	Type string        `json:",default=node,options=node|cluster"`
+//	DB   int           `json:",default=0,range=[0:15]"`
+//	Pass string        `json:",optional,env=REDIS_PASS"`
+//	Wait time.Duration `json:",default=3s"`
+//
+// default is used when the key is missing, env overrides the config with the environment variable when set,
+// a field having one of these options but neither default nor optional is required.
+// Every invalid field is reported in a *ScanError, path prefixes the field paths.
+func scanJSON(data []byte, v interface{}, path string) error {
+	err := stdjson.Unmarshal(data, v)
+	var typeErr *stdjson.UnmarshalTypeError
+	if err != nil && !errors.As(err, &typeErr) {
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return err
+	}
+	var raw interface{}
+	if err := stdjson.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	s := &scanner{converted: make(map[string]bool)}
+	s.walk(rv.Elem(), raw, path)
+	// durations written "3s" and the strings of the untyped formats are decoded by the scanner
+	if len(s.errs) == 0 && typeErr != nil && typeErr.Type != durationType && !s.converted[joinPath(path, typeErr.Field)] {
+		s.fail(joinPath(path, typeErr.Field), "cannot use %s as %s", typeErr.Value, typeErr.Type)
+	}
+	if len(s.errs) > 0 {
+		return &ScanError{Fields: s.errs}
+	}
+	return nil
+}
+
+type scanner struct {
+	errs []*FieldError
+	// converted the paths of the strings set to bool and number fields
+	converted map[string]bool
+}
+
+func (s *scanner) fail(path, format string, args ...interface{}) {
+	s.errs = append(s.errs, &FieldError{Path: path, Err: fmt.Errorf(format, args...)})
+}
+
+// walk checks raw against the type of v and applies the tag options of the nested fields, raw nil is a missing value.
+func (s *scanner) walk(v reflect.Value, raw interface{}, path string) {
+	if customUnmarshal(v.Type()) {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if raw != nil && !v.IsNil() {
+			s.walk(v.Elem(), raw, path)
+		}
+	case reflect.Interface:
+	case reflect.Struct:
+		m, ok := raw.(map[string]interface{})
+		if raw != nil && !ok {
+			s.fail(path, "expected an object, got %s", rawKind(raw))
+			return
+		}
+		s.fields(v, m, path)
+	case reflect.Slice, reflect.Array:
+		if raw == nil {
+			return
+		}
+		list, ok := raw.([]interface{})
+		if !ok {
+			if _, isStr := raw.(string); isStr && v.Type().Elem().Kind() == reflect.Uint8 {
+				return
+			}
+			s.fail(path, "expected a list, got %s", rawKind(raw))
+			return
+		}
+		for i := 0; i < v.Len() && i < len(list); i++ {
+			s.walk(v.Index(i), list[i], fmt.Sprintf("%s[%d]", path, i))
+		}
+	case reflect.Map:
+		if raw == nil {
+			return
+		}
+		m, ok := raw.(map[string]interface{})
+		if !ok {
+			s.fail(path, "expected an object, got %s", rawKind(raw))
+			return
+		}
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key := reflect.ValueOf(k).Convert(v.Type().Key())
+			elem := v.MapIndex(key)
+			if !elem.IsValid() {
+				continue
+			}
+			cp := reflect.New(elem.Type()).Elem()
+			cp.Set(elem)
+			s.walk(cp, m[k], joinPath(path, k))
+			v.SetMapIndex(key, cp)
+		}
+	default:
+		s.scalar(v, raw, path)
+	}
+}
+
+func (s *scanner) scalar(v reflect.Value, raw interface{}, path string) {
+	if raw == nil {
+		return
+	}
+	if v.Type() == durationType {
+		if str, ok := raw.(string); ok {
+			d, err := time.ParseDuration(str)
+			if err != nil {
+				s.fail(path, "invalid duration %q", str)
+				return
+			}
+			v.SetInt(int64(d))
+			return
+		}
+	}
+	// properties, ini and env values are strings, they are converted as the defaults are
+	if str, ok := raw.(string); ok && v.Kind() >= reflect.Bool && v.Kind() <= reflect.Float64 {
+		if err := setString(v, strings.TrimSpace(str)); err != nil {
+			s.fail(path, "cannot use %q as %s", str, v.Type())
+			return
+		}
+		s.converted[path] = true
+		return
+	}
+	switch v.Kind() {
+	case reflect.String:
+		if _, ok := raw.(string); !ok {
+			s.fail(path, "expected a string, got %s", rawKind(raw))
+		}
+	case reflect.Bool:
+		if _, ok := raw.(bool); !ok {
+			s.fail(path, "expected a bool, got %s", rawKind(raw))
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f, ok := raw.(float64)
+		switch {
+		case !ok:
+			s.fail(path, "expected an integer, got %s", rawKind(raw))
+		case f != math.Trunc(f):
+			s.fail(path, "expected an integer, got %v", f)
+		case v.Kind() >= reflect.Uint && f < 0:
+			s.fail(path, "expected an unsigned integer, got %v", f)
+		}
+	case reflect.Float32, reflect.Float64:
+		if _, ok := raw.(float64); !ok {
+			s.fail(path, "expected a number, got %s", rawKind(raw))
+		}
+	}
+}
+
+func (s *scanner) fields(v reflect.Value, m map[string]interface{}, path string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+		name, opts := parseTag(sf.Tag.Get("json"))
+		if name == "-" && opts == "" {
+			continue
+		}
+		fv := v.Field(i)
+		if sf.Anonymous && name == "" {
+			// embedded struct fields are promoted, as encoding/json does
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() || !fv.Elem().CanSet() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				s.fields(fv, m, path)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		key := joinPath(path, name)
+		o, err := parseOptions(opts)
+		if err != nil {
+			s.fail(key, "invalid tag: %v", err)
+			continue
+		}
+
+		raw, present := lookupKey(m, name)
+		if o.env != "" {
+			if env, ok := os.LookupEnv(o.env); ok {
+				if err := setString(fv, env); err != nil {
+					s.fail(key, "invalid value %q of env %s: %v", env, o.env, err)
+					continue
+				}
+				s.validate(fv, o, key)
+				continue
+			}
+		}
+		if !present {
+			switch {
+			case o.def != nil:
+				if err := setString(fv, *o.def); err != nil {
+					s.fail(key, "invalid default %q: %v", *o.def, err)
+					continue
+				}
+				s.validate(fv, o, key)
+			case o.required():
+				s.fail(key, "is required")
+				continue
+			case o.optional:
+				continue
+			}
+			// missing objects still get the defaults of their fields
+			if fv.Kind() == reflect.Struct {
+				s.walk(fv, nil, key)
+			}
+			continue
+		}
+		before := len(s.errs)
+		s.walk(fv, raw, key)
+		if len(s.errs) == before {
+			s.validate(fv, o, key)
+		}
+	}
+}
+
+// validate checks the options and range tag options.
+func (s *scanner) validate(v reflect.Value, o *tagOptions, path string) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	values := []reflect.Value{v}
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		values = values[:0]
+		for i := 0; i < v.Len(); i++ {
+			values = append(values, v.Index(i))
+		}
+	}
+	for _, elem := range values {
+		if len(o.options) > 0 {
+			str := fmt.Sprint(elem.Interface())
+			found := false
+			for _, opt := range o.options {
+				if opt == str {
+					found = true
+					break
+				}
+			}
+			if !found {
+				s.fail(path, "value %q is not one of [%s]", str, strings.Join(o.options, "|"))
+			}
+		}
+		if o.rng != nil {
+			f, ok := numberOf(elem)
+			if !ok {
+				s.fail(path, "range of a non numeric field")
+			} else if !o.rng.contains(f) {
+				s.fail(path, "value %v out of range %s", elem.Interface(), o.rng.text)
+			}
+		}
+	}
+}
+
+type tagOptions struct {
+	optional bool
+	def      *string
+	env      string
+	options  []string
+	rng      *numberRange
+	// tagged the go-zero options are used, which makes the field required
+	tagged bool
+}
+
+func (o *tagOptions) required() bool {
+	return o.tagged && !o.optional && o.def == nil
+}
+
+type numberRange struct {
+	text                       string
+	min, max                   *float64
+	minExclusive, maxExclusive bool
+}
+
+func (r *numberRange) contains(f float64) bool {
+	if r.min != nil && (f < *r.min || r.minExclusive && f == *r.min) {
+		return false
+	}
+	if r.max != nil && (f > *r.max || r.maxExclusive && f == *r.max) {
+		return false
+	}
+	return true
+}
+
+// parseTag splits a json tag into the name and the options.
+func parseTag(tag string) (string, string) {
+	if idx := strings.Index(tag, ","); idx >= 0 {
+		return tag[:idx], tag[idx+1:]
+	}
+	return tag, ""
+}
+
+func parseOptions(opts string) (*tagOptions, error) {
+	o := &tagOptions{}
+	for _, opt := range splitOptions(opts) {
+		key, value, hasValue := strings.Cut(opt, "=")
+		switch key {
+		case "optional":
+			o.optional, o.tagged = true, true
+		case "default":
+			o.def, o.tagged = &value, true
+		case "env":
+			o.env, o.tagged = value, true
+		case "options":
+			o.tagged = true
+			value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+			sep := "|"
+			if !strings.Contains(value, "|") {
+				sep = ","
+			}
+			for _, v := range strings.Split(value, sep) {
+				o.options = append(o.options, strings.TrimSpace(v))
+			}
+		case "range":
+			r, err := parseRange(value)
+			if err != nil {
+				return nil, err
+			}
+			o.rng, o.tagged = r, true
+		default:
+			// encoding/json options: omitempty, string
+			if hasValue {
+				return nil, fmt.Errorf("unknown option %q", key)
+			}
+		}
+	}
+	return o, nil
+}
+
+// parseRange parses [min:max], ( and ) exclude the bound, a bound may be empty.
+func parseRange(text string) (*numberRange, error) {
+	if len(text) < 3 || !strings.ContainsAny(text[:1], "[(") || !strings.ContainsAny(text[len(text)-1:], "])") {
+		return nil, fmt.Errorf("invalid range %q", text)
+	}
+	lower, upper, ok := strings.Cut(text[1:len(text)-1], ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid range %q", text)
+	}
+	r := &numberRange{text: text, minExclusive: text[0] == '(', maxExclusive: text[len(text)-1] == ')'}
+	for _, b := range []struct {
+		text string
+		dst  **float64
+	}{{lower, &r.min}, {upper, &r.max}} {
+		if b.text = strings.TrimSpace(b.text); b.text == "" {
+			continue
+		}
+		f, err := strconv.ParseFloat(b.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid range %q", text)
+		}
+		*b.dst = &f
+	}
+	if r.min != nil && r.max != nil && *r.min > *r.max {
+		return nil, fmt.Errorf("invalid range %q", text)
+	}
+	return r, nil
+}
+
+// splitOptions splits on the commas outside of brackets, default=[a,b] is one option.
+func splitOptions(opts string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+	for i, c := range opts {
+		switch c {
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, opts[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if start < len(opts) {
+		parts = append(parts, opts[start:])
+	}
+	return parts
+}
+
+// setString sets a default or env value, lists are written [a,b].
+func setString(v reflect.Value, str string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(str)
+		if err != nil {
+			n, nerr := strconv.ParseInt(str, 10, 64)
+			if nerr != nil {
+				return err
+			}
+			d = time.Duration(n)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(str)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(str, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(str, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(str, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Ptr:
+		elem := reflect.New(v.Type().Elem())
+		if err := setString(elem.Elem(), str); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Slice:
+		items := []string{str}
+		if strings.HasPrefix(str, "[") && strings.HasSuffix(str, "]") {
+			items = nil
+			if inner := strings.TrimSpace(str[1 : len(str)-1]); inner != "" {
+				items = strings.Split(inner, ",")
+			}
+		}
+		list := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setString(list.Index(i), strings.TrimSpace(item)); err != nil {
+				return err
+			}
+		}
+		v.Set(list)
+	default:
+		return stdjson.Unmarshal([]byte(str), v.Addr().Interface())
+	}
+	return nil
+}
+
+// lookupKey finds key the way encoding/json does, an exact match first then a case insensitive one.
+func lookupKey(m map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := m[key]; ok {
+		return v, v != nil
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, v != nil
+		}
+	}
+	return nil, false
+}
+
+func customUnmarshal(t reflect.Type) bool {
+	if t.Kind() != reflect.Ptr {
+		t = reflect.PtrTo(t)
+	}
+	return t.Implements(jsonUnmarshalerType) || t.Implements(textUnmarshalerType)
+}
+
+func numberOf(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+func rawKind(raw interface{}) string {
+	switch raw.(type) {
+	case string:
+		return "a string"
+	case bool:
+		return "a bool"
+	case float64:
+		return "a number"
+	case []interface{}:
+		return "a list"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", raw)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
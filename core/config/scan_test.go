@@ -0,0 +1,132 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testRedisConf struct {
+	Addrs      []string `json:",default=127.0.0.1:6379"`
+	Pass       string   `json:",optional,env=TEST_SCAN_REDIS_PASS"`
+	DB         int      `json:",default=0,range=[0:15]"`
+	Type       string   `json:",default=standalone,options=standalone|sentinel|cluster"`
+	MaxRetries int      `json:",default=1,range=[0:3]"`
+	MasterName string
+}
+
+type testScanConf struct {
+	Name    string        `json:"name"`
+	Mode    string        `json:"mode,options=dev|prod"`
+	Timeout time.Duration `json:"timeout,default=3s"`
+	Ratio   float64       `json:"ratio,default=0.5,range=(0:1]"`
+	Tags    []string      `json:"tags,default=[a,b]"`
+	Redis   testRedisConf `json:"redis"`
+	Nodes   []struct {
+		Host string `json:"host"`
+		Port int    `json:"port,default=80,range=[1:65535]"`
+	} `json:"nodes"`
+}
+
+func TestScanTags(t *testing.T) {
+	t.Setenv("TEST_SCAN_REDIS_PASS", "secret")
+	data := `{"name":"demo","mode":"prod","timeout":"10s","redis":{"type":"cluster"},"nodes":[{"host":"a"},{"host":"b","port":8080}]}`
+
+	var conf testScanConf
+	if err := scanJSON([]byte(data), &conf, ""); err != nil {
+		t.Fatal(err)
+	}
+	want := testScanConf{
+		Name:    "demo",
+		Mode:    "prod",
+		Timeout: 10 * time.Second,
+		Ratio:   0.5,
+		Tags:    []string{"a", "b"},
+		Redis: testRedisConf{
+			Addrs:      []string{"127.0.0.1:6379"},
+			Pass:       "secret",
+			Type:       "cluster",
+			MaxRetries: 1,
+		},
+	}
+	want.Nodes = append(want.Nodes, conf.Nodes...)
+	want.Nodes[0].Port = 80
+	if !reflect.DeepEqual(want, conf) {
+		t.Fatalf("want %+v, got %+v", want, conf)
+	}
+	if conf.Nodes[1].Port != 8080 {
+		t.Fatalf("want port 8080, got %d", conf.Nodes[1].Port)
+	}
+}
+
+func TestScanTagsErrors(t *testing.T) {
+	data := `{"name":1,"timeout":"soon","ratio":0,"redis":{"db":20,"type":"single","maxRetries":2},"nodes":[{"host":"a","port":70000}]}`
+
+	var conf testScanConf
+	err := scanJSON([]byte(data), &conf, "app")
+	var scanErr *ScanError
+	if !errors.As(err, &scanErr) {
+		t.Fatalf("want a *ScanError, got %v", err)
+	}
+	var paths []string
+	for _, f := range scanErr.Fields {
+		paths = append(paths, f.Path)
+	}
+	want := []string{"app.name", "app.mode", "app.timeout", "app.ratio", "app.redis.DB", "app.redis.Type", "app.nodes[0].port"}
+	if !reflect.DeepEqual(want, paths) {
+		t.Fatalf("want %v, got %v\n%v", want, paths, err)
+	}
+	for _, msg := range []string{"expected a string", "is required", "out of range (0:1]", `"single" is not one of [standalone|sentinel|cluster]`} {
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("%q not found in %v", msg, err)
+		}
+	}
+}
+
+func TestValueScanPath(t *testing.T) {
+	c := New(WithSource(newTestJSONSource(`{"data":{"redis":{"DB":16}}}`)))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	var conf testRedisConf
+	err := c.Value("data.redis").Scan(&conf)
+	if err == nil || !strings.Contains(err.Error(), "data.redis.DB: value 16 out of range [0:15]") {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		text string
+		in   []float64
+		out  []float64
+	}{
+		{"[0:15]", []float64{0, 15}, []float64{-1, 16}},
+		{"(0:1)", []float64{0.5}, []float64{0, 1}},
+		{"[1:]", []float64{1, 1e9}, []float64{0}},
+		{"(:0]", []float64{-5, 0}, []float64{1}},
+	}
+	for _, tt := range tests {
+		r, err := parseRange(tt.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range tt.in {
+			if !r.contains(f) {
+				t.Errorf("%s should contain %v", tt.text, f)
+			}
+		}
+		for _, f := range tt.out {
+			if r.contains(f) {
+				t.Errorf("%s should not contain %v", tt.text, f)
+			}
+		}
+	}
+	for _, text := range []string{"0:15", "[15:0]", "[a:1]", "[1]"} {
+		if _, err := parseRange(text); err == nil {
+			t.Errorf("%s should be invalid", text)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package config
+
+import (
+	stdjson "encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SchemaDraft the JSON Schema version of the generated schemas.
+const SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSON Schema types.
+const (
+	TypeObject  = "object"
+	TypeArray   = "array"
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+// SchemaType the types of a Schema, written as a string when there is only one.
+type SchemaType []string
+
+func (t SchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return stdjson.Marshal(t[0])
+	}
+	return stdjson.Marshal([]string(t))
+}
+
+// Schema a JSON Schema of a config struct, a Schema without Type accepts any value.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        SchemaType         `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	// AdditionalProperties false, or the *Schema of the values of a map
+	AdditionalProperties interface{}   `json:"additionalProperties,omitempty"`
+	Items                *Schema       `json:"items,omitempty"`
+	Required             []string      `json:"required,omitempty"`
+	Enum                 []interface{} `json:"enum,omitempty"`
+	Default              interface{}   `json:"default,omitempty"`
+	Minimum              *float64      `json:"minimum,omitempty"`
+	Maximum              *float64      `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64      `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64      `json:"exclusiveMaximum,omitempty"`
+}
+
+var schemas = struct {
+	sync.Mutex
+	keys map[string][]reflect.Type
+}{keys: make(map[string][]reflect.Type)}
+
+// RegisterSchema registers the struct of the config under key, "" registers a struct of the whole config.
+//
+//	config.RegisterSchema("", Config{})
+//	config.RegisterSchema("db", gormx.Options{})
+func RegisterSchema(key string, v interface{}) {
+	schemas.Lock()
+	defer schemas.Unlock()
+	schemas.keys[key] = append(schemas.keys[key], reflect.TypeOf(v))
+}
+
+// RegisteredSchema returns the schema of the whole config built from the registered structs, nil when none is.
+func RegisteredSchema() *Schema {
+	schemas.Lock()
+	defer schemas.Unlock()
+	if len(schemas.keys) == 0 {
+		return nil
+	}
+	root := &Schema{Schema: SchemaDraft, Type: SchemaType{TypeObject}, Properties: map[string]*Schema{}, AdditionalProperties: false}
+	keys := make([]string, 0, len(schemas.keys))
+	for k := range schemas.keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, t := range schemas.keys[k] {
+			addSchema(root, k, schemaOf(t, map[reflect.Type]bool{}))
+		}
+	}
+	return root
+}
+
+// addSchema merges s into the object schema at the dotted key of root, the missing objects on the
+// way are created and required when s has required fields.
+func addSchema(root *Schema, key string, s *Schema) {
+	node := root
+	if key != "" {
+		segments := strings.Split(key, ".")
+		for i, name := range segments {
+			if len(s.Required) > 0 {
+				node.Required = appendMissing(node.Required, name)
+			}
+			child, ok := node.Properties[name]
+			if !ok {
+				if i == len(segments)-1 {
+					node.Properties[name] = s
+					return
+				}
+				child = &Schema{Type: SchemaType{TypeObject}, AdditionalProperties: false}
+				node.Properties[name] = child
+			}
+			if child.Properties == nil {
+				child.Properties = map[string]*Schema{}
+			}
+			node = child
+		}
+	}
+	mergeSchema(node, s)
+}
+
+// mergeSchema adds the properties and required fields of src to dst, the objects defined by both are merged.
+func mergeSchema(dst, src *Schema) {
+	for name, p := range src.Properties {
+		if q, ok := dst.Properties[name]; ok && q.Properties != nil && p.Properties != nil {
+			mergeSchema(q, p)
+			continue
+		}
+		dst.Properties[name] = p
+	}
+	dst.Required = appendMissing(dst.Required, src.Required...)
+}
+
+func appendMissing(list []string, items ...string) []string {
+	for _, item := range items {
+		found := false
+		for _, v := range list {
+			if v == item {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// SchemaOf returns the schema of the config struct v, the go-zero options of its json tags are
+// translated: default, options to enum, range to the bounds, the fields they make required.
+func SchemaOf(v interface{}) *Schema {
+	s := schemaOf(reflect.TypeOf(v), map[reflect.Type]bool{})
+	s.Schema = SchemaDraft
+	return s
+}
+
+func schemaOf(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return &Schema{}
+	}
+	if t == durationType {
+		return &Schema{Type: SchemaType{TypeString, TypeInteger}, Format: "duration"}
+	}
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return &Schema{Type: SchemaType{TypeString}}
+	}
+	if customUnmarshal(t) {
+		return &Schema{}
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: SchemaType{TypeBoolean}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: SchemaType{TypeInteger}}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: SchemaType{TypeInteger}, Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: SchemaType{TypeNumber}}
+	case reflect.String:
+		return &Schema{Type: SchemaType{TypeString}}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: SchemaType{TypeString}, Format: "byte"}
+		}
+		return &Schema{Type: SchemaType{TypeArray}, Items: schemaOf(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Schema{Type: SchemaType{TypeObject}, AdditionalProperties: schemaOf(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			// a recursive type
+			return &Schema{Type: SchemaType{TypeObject}}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		s := &Schema{Title: t.Name(), Type: SchemaType{TypeObject}, Properties: map[string]*Schema{}, AdditionalProperties: false}
+		fieldsSchema(s, t, visiting)
+		return s
+	}
+	// interfaces, funcs and channels accept anything
+	return &Schema{}
+}
+
+// fieldsSchema adds the fields of t to s as scanJSON reads them.
+func fieldsSchema(s *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() && !sf.Anonymous {
+			continue
+		}
+		name, opts := parseTag(sf.Tag.Get("json"))
+		if name == "-" && opts == "" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fieldsSchema(s, ft, visiting)
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		p := schemaOf(sf.Type, visiting)
+		o, err := parseOptions(opts)
+		if err != nil {
+			p.Description = fmt.Sprintf("invalid tag: %v", err)
+			s.Properties[name] = p
+			continue
+		}
+		applyOptions(p, sf.Type, o, splitOptions(opts))
+		s.Properties[name] = p
+		// a missing struct, not a pointer, fails on its required fields
+		if o.required() || (sf.Type.Kind() == reflect.Struct && len(p.Required) > 0 && !o.optional && o.def == nil) {
+			s.Required = append(s.Required, name)
+		}
+	}
+	sort.Strings(s.Required)
+}
+
+func applyOptions(s *Schema, t reflect.Type, o *tagOptions, opts []string) {
+	for _, opt := range opts {
+		// encoding/json reads the scalars of the ,string fields from json strings
+		if opt == "string" && len(s.Type) == 1 && s.Type[0] != TypeObject && s.Type[0] != TypeArray {
+			s.Type = SchemaType{TypeString}
+		}
+	}
+	if o.def != nil {
+		s.Default = typedValue(t, *o.def)
+	}
+	for _, opt := range o.options {
+		s.Enum = append(s.Enum, typedValue(t, opt))
+	}
+	if r := o.rng; r != nil {
+		if r.minExclusive {
+			s.ExclusiveMinimum = r.min
+		} else if r.min != nil {
+			s.Minimum = r.min
+		}
+		if r.maxExclusive {
+			s.ExclusiveMaximum = r.max
+		} else {
+			s.Maximum = r.max
+		}
+	}
+	if o.env != "" {
+		s.Description = "overridden by the environment variable " + o.env
+	}
+}
+
+// typedValue returns str as a json value of the type t, str itself when it does not convert.
+func typedValue(t reflect.Type, str string) interface{} {
+	if t == durationType {
+		return str
+	}
+	v := reflect.New(t).Elem()
+	if err := setString(v, str); err != nil {
+		return str
+	}
+	data, err := stdjson.Marshal(v.Interface())
+	if err != nil {
+		return str
+	}
+	var res interface{}
+	if stdjson.Unmarshal(data, &res) != nil {
+		return str
+	}
+	return res
+}
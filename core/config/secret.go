@@ -0,0 +1,184 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// SecretProvider decrypts or looks up the secrets of a scheme, see package secrets.
+type SecretProvider interface {
+	// Scheme the name of the provider in the references: ENC(aes:...), ${secret:file:db_password}
+	Scheme() string
+	// Resolve returns the plain text of ref
+	Resolve(ref string) (string, error)
+}
+
+var (
+	encPattern    = regexp.MustCompile(`ENC\(([^)]*)\)`)
+	secretPattern = regexp.MustCompile(`\$\{secret:([^:}]+):([^}]*)\}`)
+)
+
+// WithSecrets resolves the secret references of the string values with providers, after the placeholders:
+//
+//	password: ENC(aes:nonce+ciphertext in base64)
+//	password: ENC(base64)                       # the first provider
+//	password: ${secret:file:db_password}        # file db_password of the mounted secrets directory
+//	password: ${secret:age:/etc/app/secrets.yaml.age#db.password}
+//
+// Resolved values are not logged, Load only logs the content of the sources.
+func WithSecrets(providers ...SecretProvider) Option {
+	return func(o *options) {
+		o.secrets = append(o.secrets, providers...)
+	}
+}
+
+type secretResolver struct {
+	providers map[string]SecretProvider
+	first     SecretProvider
+
+	lock sync.Mutex
+	// paths config paths holding a resolved secret
+	paths map[string]bool
+}
+
+func newSecretResolver(providers []SecretProvider) *secretResolver {
+	r := &secretResolver{providers: make(map[string]SecretProvider), paths: make(map[string]bool)}
+	for _, p := range providers {
+		if r.first == nil {
+			r.first = p
+		}
+		r.providers[p.Scheme()] = p
+	}
+	return r
+}
+
+// chain returns a Resolver calling next then resolving the secrets, so a ${...} in a plain text
+// is kept as is, and a placeholder copying a reference gets its plain text too.
+func (r *secretResolver) chain(next Resolver) Resolver {
+	return func(input map[string]interface{}) error {
+		if err := next(input); err != nil {
+			return err
+		}
+		return r.resolve("", input)
+	}
+}
+
+func (r *secretResolver) resolve(prefix string, sub map[string]interface{}) error {
+	for k, v := range sub {
+		path := joinPath(prefix, k)
+		switch vt := v.(type) {
+		case string:
+			s, err := r.expand(path, vt)
+			if err != nil {
+				return err
+			}
+			sub[k] = s
+		case map[string]interface{}:
+			if err := r.resolve(path, vt); err != nil {
+				return err
+			}
+		case []interface{}:
+			for i, item := range vt {
+				itemPath := fmt.Sprintf("%s[%d]", path, i)
+				switch it := item.(type) {
+				case string:
+					s, err := r.expand(itemPath, it)
+					if err != nil {
+						return err
+					}
+					vt[i] = s
+				case map[string]interface{}:
+					if err := r.resolve(itemPath, it); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// expand replaces the references of s, the errors name the path but never the value.
+func (r *secretResolver) expand(path, s string) (string, error) {
+	var err error
+	replace := func(scheme, ref string) string {
+		p := r.providers[scheme]
+		if p == nil {
+			err = fmt.Errorf("config: %s: unknown secret scheme %q", path, scheme)
+			return ""
+		}
+		plain, perr := p.Resolve(ref)
+		if perr != nil {
+			err = fmt.Errorf("config: %s: resolve %s secret: %w", path, scheme, perr)
+			return ""
+		}
+		r.markSecret(path)
+		return plain
+	}
+	s = encPattern.ReplaceAllStringFunc(s, func(m string) string {
+		ref := encPattern.FindStringSubmatch(m)[1]
+		if idx := indexScheme(ref); idx > 0 {
+			return replace(ref[:idx], ref[idx+1:])
+		}
+		if r.first == nil {
+			err = fmt.Errorf("config: %s: no secret provider", path)
+			return ""
+		}
+		return replace(r.first.Scheme(), ref)
+	})
+	s = secretPattern.ReplaceAllStringFunc(s, func(m string) string {
+		sm := secretPattern.FindStringSubmatch(m)
+		return replace(sm[1], sm[2])
+	})
+	return s, err
+}
+
+// indexScheme returns the index of the colon ending the scheme of ENC(scheme:data), base64 data has no colon.
+func indexScheme(ref string) int {
+	for i, c := range ref {
+		switch {
+		case c == ':':
+			return i
+		case c >= 'a' && c <= 'z', c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return -1
+		}
+	}
+	return -1
+}
+
+func (r *secretResolver) markSecret(path string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.paths[path] = true
+}
+
+// isSecret reports whether path or one of its parents holds a resolved secret.
+func (r *secretResolver) isSecret(path string) bool {
+	if r == nil {
+		return false
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for p := path; p != ""; {
+		if r.paths[p] {
+			return true
+		}
+		idx := lastSeparator(p)
+		if idx < 0 {
+			break
+		}
+		p = p[:idx]
+	}
+	return false
+}
+
+func lastSeparator(path string) int {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '.' || path[i] == '[' {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testSecretProvider struct {
+	scheme string
+}
+
+func (p testSecretProvider) Scheme() string { return p.scheme }
+
+func (p testSecretProvider) Resolve(ref string) (string, error) {
+	if ref == "bad" {
+		return "", errors.New("cannot decrypt")
+	}
+	return p.scheme + "-" + strings.ToUpper(ref), nil
+}
+
+func TestWithSecrets(t *testing.T) {
+	c := New(
+		WithSource(newTestJSONSource(`{"db":{"password":"ENC(abc)","user":"${db.name}","name":"root"},"redis":{"pass":"ENC(vault:xyz)","addrs":["pre-${secret:file:addr}"]}}`)),
+		WithSecrets(testSecretProvider{"aes"}, testSecretProvider{"vault"}, testSecretProvider{"file"}),
+	)
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{
+		"db.password": "aes-ABC",
+		"db.user":     "root",
+		"redis.pass":  "vault-XYZ",
+	} {
+		if v, _ := c.Value(key).String(); v != want {
+			t.Errorf("%s: want %s, got %s", key, want, v)
+		}
+	}
+	var addrs []string
+	if err := c.Value("redis.addrs").Scan(&addrs); err != nil || addrs[0] != "pre-file-ADDR" {
+		t.Fatalf("unexpected %v %v", addrs, err)
+	}
+
+	secrets := c.(*config).secrets
+	for path, want := range map[string]bool{"db.password": true, "redis.addrs[0]": true, "db.user": false, "db": false} {
+		if secrets.isSecret(path) != want {
+			t.Errorf("isSecret(%s) want %v", path, want)
+		}
+	}
+}
+
+type staticSecretProvider string
+
+func (p staticSecretProvider) Scheme() string { return "static" }
+
+func (p staticSecretProvider) Resolve(string) (string, error) { return string(p), nil }
+
+func TestSecretsAfterPlaceholders(t *testing.T) {
+	c := New(
+		WithSource(newTestJSONSource(`{"name":"root","db":{"password":"ENC(x)","copy":"${db.password}"}}`)),
+		WithSecrets(staticSecretProvider("p@${name}")),
+	)
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"db.password", "db.copy"} {
+		if v, _ := c.Value(key).String(); v != "p@${name}" {
+			t.Errorf("%s: the plain text must be kept, got %s", key, v)
+		}
+		if !c.(*config).secrets.isSecret(key) {
+			t.Errorf("isSecret(%s) want true", key)
+		}
+	}
+}
+
+func TestWithSecretsErrors(t *testing.T) {
+	for data, msg := range map[string]string{
+		`{"a":{"b":"ENC(bad)"}}`:         "a.b: resolve aes secret: cannot decrypt",
+		`{"a":"${secret:vault:x}"}`:      `a: unknown secret scheme "vault"`,
+		`{"a":["ENC(other:bad)", "ok"]}`: `a[0]: unknown secret scheme "other"`,
+	} {
+		c := New(WithSource(newTestJSONSource(data)), WithSecrets(testSecretProvider{"aes"}))
+		if err := c.Load(); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%s: want %q, got %v", data, msg, err)
+		}
+	}
+
+	// without providers the references are left as they are
+	c := New(WithSource(newTestJSONSource(`{"a":"${secret:file:x}","b":"ENC(abc)"}`)))
+	if err := c.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := c.Value("a").String(); v != "${secret:file:x}" {
+		t.Errorf("unexpected %s", v)
+	}
+}
@@ -0,0 +1,90 @@
+// Package secrets provides the secret providers of config.WithSecrets.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SchemeAES scheme of the AES-GCM provider.
+const SchemeAES = "aes"
+
+// AESGCM decrypts ENC(aes:<base64 of nonce+ciphertext>) values.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM new an AES-GCM provider of key, 16, 24 or 32 bytes.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// AESGCMKeyFile new an AES-GCM provider of the base64 key stored in path.
+func AESGCMKeyFile(path string) (*AESGCM, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return newAESGCMBase64(string(data))
+}
+
+// AESGCMKeyEnv new an AES-GCM provider of the base64 key in the env variable name.
+func AESGCMKeyEnv(name string) (*AESGCM, error) {
+	key, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("secrets: env %s not set", name)
+	}
+	return newAESGCMBase64(key)
+}
+
+func newAESGCMBase64(key string) (*AESGCM, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil {
+		return nil, fmt.Errorf("secrets: invalid base64 key: %w", err)
+	}
+	return NewAESGCM(raw)
+}
+
+func (a *AESGCM) Scheme() string {
+	return SchemeAES
+}
+
+func (a *AESGCM) Resolve(ref string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(ref))
+	if err != nil {
+		return "", err
+	}
+	size := a.aead.NonceSize()
+	if len(data) < size {
+		return "", errors.New("ciphertext too short")
+	}
+	plain, err := a.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+// Encrypt returns the ENC(aes:...) value of plain, to write in the config files.
+func (a *AESGCM) Encrypt(plain string) (string, error) {
+	nonce := make([]byte, a.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	data := a.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return "ENC(" + SchemeAES + ":" + base64.StdEncoding.EncodeToString(data) + ")", nil
+}
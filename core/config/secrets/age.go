@@ -0,0 +1,127 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemeAge scheme of the age provider.
+const SchemeAge = "age"
+
+// ageMagic base64 prefix of the age header "age-encryption.org/"
+const ageMagic = "YWdlLWVuY3J5cHRpb24ub3JnL"
+
+// Age decrypts age encrypted files, sops style:
+//
+//	${secret:age:/etc/app/secrets.yaml.age}               the whole file
+//	${secret:age:/etc/app/secrets.yaml.age#db.password}   a key of the decrypted YAML or JSON
+//	ENC(age:<base64 of the binary age file>)
+//
+// Files may be binary or armored.
+type Age struct {
+	identities []age.Identity
+}
+
+// NewAge new an age provider decrypting with identities.
+func NewAge(identities ...age.Identity) *Age {
+	return &Age{identities: identities}
+}
+
+// AgeIdentityFile new an age provider of the identities in path, as written by age-keygen.
+func AgeIdentityFile(path string) (*Age, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseIdentities(f)
+}
+
+// AgeIdentityEnv new an age provider of the identities in the env variable name.
+func AgeIdentityEnv(name string) (*Age, error) {
+	key, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("secrets: env %s not set", name)
+	}
+	return parseIdentities(strings.NewReader(key))
+}
+
+func parseIdentities(r io.Reader) (*Age, error) {
+	ids, err := age.ParseIdentities(r)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: parse age identities: %w", err)
+	}
+	return NewAge(ids...), nil
+}
+
+func (a *Age) Scheme() string {
+	return SchemeAge
+}
+
+func (a *Age) Resolve(ref string) (string, error) {
+	var (
+		data []byte
+		key  string
+		err  error
+	)
+	if strings.HasPrefix(ref, ageMagic) {
+		if data, err = base64.StdEncoding.DecodeString(ref); err != nil {
+			return "", err
+		}
+	} else {
+		path := ref
+		if idx := strings.LastIndex(ref, "#"); idx >= 0 {
+			path, key = ref[:idx], ref[idx+1:]
+		}
+		if data, err = os.ReadFile(path); err != nil {
+			return "", err
+		}
+	}
+
+	var r io.Reader = bytes.NewReader(data)
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte(armor.Header)) {
+		r = armor.NewReader(bytes.NewReader(bytes.TrimSpace(data)))
+	}
+	dr, err := age.Decrypt(r, a.identities...)
+	if err != nil {
+		return "", err
+	}
+	plain, err := io.ReadAll(dr)
+	if err != nil {
+		return "", err
+	}
+	if key == "" {
+		return strings.TrimRight(string(plain), "\r\n"), nil
+	}
+	return lookup(plain, key)
+}
+
+// lookup returns the scalar at the dotted key of the YAML or JSON document.
+func lookup(doc []byte, key string) (string, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(doc, &v); err != nil {
+		return "", fmt.Errorf("decrypted document: %w", err)
+	}
+	for _, k := range strings.Split(key, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("key %q not found", key)
+		}
+		if v, ok = m[k]; !ok {
+			return "", fmt.Errorf("key %q not found", key)
+		}
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}, nil:
+		return "", fmt.Errorf("key %q is not a scalar", key)
+	}
+	return fmt.Sprint(v), nil
+}
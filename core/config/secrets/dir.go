@@ -0,0 +1,38 @@
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SchemeFile scheme of the secrets directory provider.
+const SchemeFile = "file"
+
+// Dir reads ${secret:file:<name>} from the files of a mounted secrets directory, as a Kubernetes Secret volume.
+type Dir struct {
+	dir string
+}
+
+// NewDir new a provider of the files of dir.
+func NewDir(dir string) *Dir {
+	return &Dir{dir: dir}
+}
+
+func (d *Dir) Scheme() string {
+	return SchemeFile
+}
+
+// Resolve returns the content of the file name without the trailing newline.
+func (d *Dir) Resolve(name string) (string, error) {
+	clean := filepath.Clean(name)
+	if filepath.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("secret %q outside of the directory", name)
+	}
+	data, err := os.ReadFile(filepath.Join(d.dir, clean))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+func TestAESGCM(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+	t.Setenv("TEST_SECRETS_KEY", key)
+	a, err := AESGCMKeyEnv("TEST_SECRETS_KEY")
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := a.Encrypt("p@ss")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enc, "ENC(aes:") {
+		t.Fatalf("unexpected %s", enc)
+	}
+	plain, err := a.Resolve(strings.TrimSuffix(strings.TrimPrefix(enc, "ENC(aes:"), ")"))
+	if err != nil || plain != "p@ss" {
+		t.Fatalf("want p@ss, got %q %v", plain, err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{8}, 32))+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	other, err := AESGCMKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Resolve(strings.TrimSuffix(strings.TrimPrefix(enc, "ENC(aes:"), ")")); err == nil {
+		t.Fatal("decrypted with the wrong key")
+	}
+}
+
+func TestDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "db_password"), []byte("s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDir(dir)
+	if v, err := d.Resolve("db_password"); err != nil || v != "s3cret" {
+		t.Fatalf("want s3cret, got %q %v", v, err)
+	}
+	for _, name := range []string{"../etc/passwd", "/etc/passwd", "missing"} {
+		if _, err := d.Resolve(name); err == nil {
+			t.Errorf("%s should fail", name)
+		}
+	}
+}
+
+func encryptAge(t *testing.T, r age.Recipient, plain string, armored bool) []byte {
+	var buf bytes.Buffer
+	var dst io.Writer = &buf
+	var aw io.WriteCloser
+	if armored {
+		aw = armor.NewWriter(&buf)
+		dst = aw
+	}
+	w, err := age.Encrypt(dst, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(w, plain); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if aw != nil {
+		if err := aw.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestAge(t *testing.T) {
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	idPath := filepath.Join(dir, "key.txt")
+	if err := os.WriteFile(idPath, []byte("# created: test\n"+id.String()+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := AgeIdentityFile(idPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(dir, "secrets.yaml.age")
+	if err := os.WriteFile(file, encryptAge(t, id.Recipient(), "db:\n  password: pw\n  port: 3306\n", true), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for ref, want := range map[string]string{
+		file + "#db.password": "pw",
+		file + "#db.port":     "3306",
+	} {
+		if v, err := a.Resolve(ref); err != nil || v != want {
+			t.Errorf("%s: want %q, got %q %v", ref, want, v, err)
+		}
+	}
+	if _, err := a.Resolve(file + "#db"); err == nil {
+		t.Error("an object is not a secret")
+	}
+
+	inline := base64.StdEncoding.EncodeToString(encryptAge(t, id.Recipient(), "token\n", false))
+	if v, err := a.Resolve(inline); err != nil || v != "token" {
+		t.Fatalf("want token, got %q %v", v, err)
+	}
+}
@@ -45,8 +45,9 @@ func (e *env) load(envStrings []string) []*sources.KeyValue {
 
 		if len(k) != 0 {
 			kv = append(kv, &sources.KeyValue{
-				Key:   k,
-				Value: []byte(v),
+				Key:      k,
+				Value:    []byte(v),
+				Priority: sources.PriorityEnv,
 			})
 		}
 	}
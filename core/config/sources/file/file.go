@@ -36,9 +36,10 @@ func (f *file) loadFile(path string) (*sources.KeyValue, error) {
 		return nil, err
 	}
 	return &sources.KeyValue{
-		Key:    info.Name(),
-		Format: format(info.Name()),
-		Value:  data,
+		Key:      info.Name(),
+		Format:   format(info.Name()),
+		Value:    data,
+		Priority: sources.PriorityFile,
 	}, nil
 }
 
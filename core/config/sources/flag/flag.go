@@ -0,0 +1,148 @@
+// Package flag is a config source of the command line flags, flag names are dotted config keys:
+//
+//	app --db.host=10.0.0.1 --db.port 3306 --debug
+//
+// gives {"db": {"host": "10.0.0.1", "port": 3306}, "debug": true}.
+// Its KeyValues have the flag priority, they override the files and the env.
+package flag
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+
+	"github.com/spf13/pflag"
+)
+
+var _ sources.Source = (*flagSource)(nil)
+
+type flagSource struct {
+	load func() []*sources.KeyValue
+}
+
+// NewSource new a source of args, as os.Args[1:].
+// Only "--key=value", "--key value" and "--key" (true) are read, the other args and the ones after "--" are ignored.
+// Values are typed as in YAML: numbers, true and false; quote them to keep a string: --name='"007"'.
+// Numbers are typed only when their text survives the conversion, "1.10" and "007" stay strings.
+// A negative number is a value: --db.port -1.
+func NewSource(args []string) sources.Source {
+	return &flagSource{load: func() []*sources.KeyValue {
+		var kvs []*sources.KeyValue
+		for i := 0; i < len(args); i++ {
+			arg := args[i]
+			if arg == "--" {
+				break
+			}
+			if !strings.HasPrefix(arg, "--") || len(arg) == 2 {
+				continue
+			}
+			key, value, ok := strings.Cut(arg[2:], "=")
+			if !ok {
+				value = "true"
+				if i+1 < len(args) && (!strings.HasPrefix(args[i+1], "-") || isNumber(args[i+1])) {
+					value = args[i+1]
+					i++
+				}
+			}
+			kvs = append(kvs, newKeyValue(key, parse(value)))
+		}
+		return kvs
+	}}
+}
+
+// NewFlagSetSource new a source of the flags of fs set on the command line, the defaults of the flags are not used.
+// Call it after fs.Parse.
+func NewFlagSetSource(fs *pflag.FlagSet) sources.Source {
+	return &flagSource{load: func() []*sources.KeyValue {
+		var kvs []*sources.KeyValue
+		fs.Visit(func(f *pflag.Flag) {
+			kvs = append(kvs, newKeyValue(f.Name, flagValue(f)))
+		})
+		return kvs
+	}}
+}
+
+func (s *flagSource) Load() ([]*sources.KeyValue, error) {
+	return s.load(), nil
+}
+
+func (s *flagSource) Watch() (sources.Watcher, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &watcher{ctx: ctx, cancel: cancel}, nil
+}
+
+// newKeyValue nests value under the dotted key, the key of the KeyValue is the flag.
+func newKeyValue(key string, value interface{}) *sources.KeyValue {
+	keys := strings.Split(key, ".")
+	for i := len(keys) - 1; i >= 0; i-- {
+		value = map[string]interface{}{keys[i]: value}
+	}
+	data, _ := json.Marshal(value)
+	return &sources.KeyValue{Key: "--" + key, Value: data, Format: "json", Priority: sources.PriorityFlag}
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil
+}
+
+func parse(value string) interface{} {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	// the text of a number that would not be written back the same, as 1.10, is kept
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(n, 10) == value {
+		return n
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == value {
+		return f
+	}
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func flagValue(f *pflag.Flag) interface{} {
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		items := sv.GetSlice()
+		if strings.HasPrefix(f.Value.Type(), "string") {
+			return items
+		}
+		list := make([]interface{}, len(items))
+		for i, item := range items {
+			list[i] = parse(item)
+		}
+		return list
+	}
+	switch t := f.Value.Type(); {
+	case t == "bool", strings.HasPrefix(t, "int"), strings.HasPrefix(t, "uint"):
+		return parse(f.Value.String())
+	case strings.HasPrefix(t, "float"):
+		if v, err := strconv.ParseFloat(f.Value.String(), 64); err == nil {
+			return v
+		}
+	}
+	return f.Value.String()
+}
+
+type watcher struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var _ sources.Watcher = (*watcher)(nil)
+
+// Next blocks until Stop, the flags do not change.
+func (w *watcher) Next() ([]*sources.KeyValue, error) {
+	<-w.ctx.Done()
+	return nil, w.ctx.Err()
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	return nil
+}
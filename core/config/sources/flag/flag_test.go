@@ -0,0 +1,71 @@
+package flag
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+
+	"github.com/spf13/pflag"
+)
+
+func decode(t *testing.T, kvs []*sources.KeyValue) map[string]interface{} {
+	res := make(map[string]interface{})
+	for _, kv := range kvs {
+		if kv.Priority != sources.PriorityFlag || kv.Format != "json" {
+			t.Fatalf("unexpected key value %+v", kv)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(kv.Value, &m); err != nil {
+			t.Fatal(err)
+		}
+		res[kv.Key] = m
+	}
+	return res
+}
+
+func TestNewSource(t *testing.T) {
+	kvs, err := NewSource([]string{"serve", "--db.host=10.0.0.1", "--db.port", "3306", "-v", "--debug", "--name='007'",
+		"--offset", "-1", "--ratio", "-0.5", "--verbose", "-x", "--version", "1.10", "--code=007", "--rate=2.5", "--", "--ignored=1"}).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"--db.host": map[string]interface{}{"db": map[string]interface{}{"host": "10.0.0.1"}},
+		"--db.port": map[string]interface{}{"db": map[string]interface{}{"port": float64(3306)}},
+		"--debug":   map[string]interface{}{"debug": true},
+		"--name":    map[string]interface{}{"name": "007"},
+		"--offset":  map[string]interface{}{"offset": float64(-1)},
+		"--ratio":   map[string]interface{}{"ratio": -0.5},
+		"--verbose": map[string]interface{}{"verbose": true},
+		"--version": map[string]interface{}{"version": "1.10"},
+		"--code":    map[string]interface{}{"code": "007"},
+		"--rate":    map[string]interface{}{"rate": 2.5},
+	}
+	if got := decode(t, kvs); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestNewFlagSetSource(t *testing.T) {
+	fs := pflag.NewFlagSet("app", pflag.ContinueOnError)
+	fs.String("db.host", "localhost", "")
+	fs.Int("db.port", 3306, "")
+	fs.StringSlice("redis.addrs", nil, "")
+	fs.Bool("debug", false, "")
+	if err := fs.Parse([]string{"--db.port=5432", "--redis.addrs=a:6379,b:6379"}); err != nil {
+		t.Fatal(err)
+	}
+	kvs, err := NewFlagSetSource(fs).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"--db.port":     map[string]interface{}{"db": map[string]interface{}{"port": float64(5432)}},
+		"--redis.addrs": map[string]interface{}{"redis": map[string]interface{}{"addrs": []interface{}{"a:6379", "b:6379"}}},
+	}
+	if got := decode(t, kvs); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,388 @@
+// Package http is a config source polling a JSON, YAML or any other registered format document from a config server.
+//
+// The document is requested with If-None-Match, so an unchanged config costs a 304.
+// With WithHMAC the body must be signed with the shared key in the signature header.
+// Every good copy is written to the cache file with its signature, Load falls back to it when the server
+// is down at boot, after checking the signature again.
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+	"github.com/kweaver-ai/idrm-go-frame/core/encoding"
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+)
+
+const (
+	// DefaultInterval time between two polls.
+	DefaultInterval = 30 * time.Second
+	// DefaultSignatureHeader header of the HMAC-SHA256 signature of the body: "sha256=<hex>".
+	DefaultSignatureHeader = "X-Config-Signature"
+	// DefaultMaxBackoff upper bound of the wait after consecutive failures.
+	DefaultMaxBackoff = 5 * time.Minute
+	// DefaultMaxBodySize upper bound of the document size.
+	DefaultMaxBodySize = 10 << 20
+)
+
+var (
+	_ sources.Source  = (*Source)(nil)
+	_ sources.Watcher = (*Source)(nil)
+
+	// ErrSignature the body does not match its signature.
+	ErrSignature = errors.New("config http: invalid signature")
+)
+
+// Option is http source option.
+type Option func(*Source)
+
+// WithClient sets the http client, its Timeout must exceed the hold time of a long polling server.
+func WithClient(c *http.Client) Option {
+	return func(s *Source) {
+		s.client = c
+	}
+}
+
+// WithInterval sets the time between two polls, 0 long polls: the next request is sent as soon as
+// the previous one answers, the server holds it until the config changes or it times out with a 304.
+func WithInterval(d time.Duration) Option {
+	return func(s *Source) {
+		s.interval = d
+	}
+}
+
+// WithBackoff sets the wait after the first failure, doubled on each consecutive failure up to max.
+func WithBackoff(initial, max time.Duration) Option {
+	return func(s *Source) {
+		s.initialBackoff, s.maxBackoff = initial, max
+	}
+}
+
+// WithHMAC requires the body to be signed with key in header, DefaultSignatureHeader when empty.
+func WithHMAC(key []byte, header string) Option {
+	return func(s *Source) {
+		s.hmacKey = key
+		if header != "" {
+			s.signatureHeader = header
+		}
+	}
+}
+
+// WithCacheFile sets the file keeping the last good copy.
+func WithCacheFile(path string) Option {
+	return func(s *Source) {
+		s.cacheFile = path
+	}
+}
+
+// WithFormat sets the format of the document, by default the Content-Type or the extension of the url, then json.
+func WithFormat(format string) Option {
+	return func(s *Source) {
+		s.format = format
+	}
+}
+
+// WithMaxBodySize sets the largest document accepted, DefaultMaxBodySize by default.
+func WithMaxBodySize(n int64) Option {
+	return func(s *Source) {
+		if n > 0 {
+			s.maxBodySize = n
+		}
+	}
+}
+
+// WithHeader adds a request header, as an authorization token.
+func WithHeader(key, value string) Option {
+	return func(s *Source) {
+		s.header.Add(key, value)
+	}
+}
+
+// Source polls url, it is its own Watcher.
+type Source struct {
+	url             string
+	client          *http.Client
+	interval        time.Duration
+	initialBackoff  time.Duration
+	maxBackoff      time.Duration
+	hmacKey         []byte
+	signatureHeader string
+	cacheFile       string
+	format          string
+	maxBodySize     int64
+	header          http.Header
+
+	mu   sync.Mutex
+	etag string
+	// sum checksum of the last document, for the servers ignoring If-None-Match
+	sum [sha256.Size]byte
+	// failures consecutive failed polls
+	failures int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewSource new a source polling url.
+func NewSource(url string, opts ...Option) *Source {
+	s := &Source{
+		url:             url,
+		client:          &http.Client{Timeout: time.Minute},
+		interval:        DefaultInterval,
+		initialBackoff:  time.Second,
+		maxBackoff:      DefaultMaxBackoff,
+		signatureHeader: DefaultSignatureHeader,
+		maxBodySize:     DefaultMaxBodySize,
+		header:          make(http.Header),
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	return s
+}
+
+// Load fetches the document, or reads the cache file when the server fails.
+func (s *Source) Load() ([]*sources.KeyValue, error) {
+	s.mu.Lock()
+	s.etag, s.sum = "", [sha256.Size]byte{}
+	s.mu.Unlock()
+	kv, err := s.fetch(s.ctx)
+	if err == nil {
+		return []*sources.KeyValue{kv}, nil
+	}
+	cached, cerr := s.readCache()
+	if cerr != nil {
+		return nil, err
+	}
+	zapx.Warnf("config http: %s unavailable, load the cached copy %s: %v", s.url, s.cacheFile, err)
+	return []*sources.KeyValue{cached}, nil
+}
+
+func (s *Source) Watch() (sources.Watcher, error) {
+	return s, nil
+}
+
+// Next polls until the document changes, failures are logged and retried with backoff.
+func (s *Source) Next() ([]*sources.KeyValue, error) {
+	for {
+		timer := time.NewTimer(s.wait())
+		select {
+		case <-s.ctx.Done():
+			timer.Stop()
+			return nil, s.ctx.Err()
+		case <-timer.C:
+		}
+		kv, err := s.fetch(s.ctx)
+		switch {
+		case err == nil && kv != nil:
+			return []*sources.KeyValue{kv}, nil
+		case errors.Is(err, context.Canceled):
+			return nil, err
+		case err != nil:
+			zapx.Warnf("config http: poll %s: %v", s.url, err)
+		}
+	}
+}
+
+func (s *Source) Stop() error {
+	s.cancel()
+	return nil
+}
+
+// wait returns the time before the next poll, backing off after failures.
+func (s *Source) wait() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures == 0 {
+		return s.interval
+	}
+	d := s.initialBackoff
+	for i := 1; i < s.failures && d < s.maxBackoff; i++ {
+		d *= 2
+	}
+	if d > s.maxBackoff {
+		d = s.maxBackoff
+	}
+	return d
+}
+
+// fetch requests the document, it returns nil without error when it did not change.
+func (s *Source) fetch(ctx context.Context) (kv *sources.KeyValue, err error) {
+	defer func() {
+		s.mu.Lock()
+		if err != nil {
+			s.failures++
+		} else {
+			s.failures = 0
+		}
+		s.mu.Unlock()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range s.header {
+		req.Header[k] = vs
+	}
+	s.mu.Lock()
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+	s.mu.Unlock()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return nil, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("config http: %s answered %s", s.url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, s.maxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > s.maxBodySize {
+		return nil, fmt.Errorf("config http: %s is larger than %d bytes", s.url, s.maxBodySize)
+	}
+	signature := resp.Header.Get(s.signatureHeader)
+	if err := s.verify(body, signature); err != nil {
+		return nil, err
+	}
+
+	kv = &sources.KeyValue{Key: s.url, Value: body, Format: s.formatOf(resp.Header.Get("Content-Type"))}
+	etag, sum := resp.Header.Get("ETag"), sha256.Sum256(body)
+	s.mu.Lock()
+	unchanged := sum == s.sum
+	s.etag, s.sum = etag, sum
+	s.mu.Unlock()
+	if unchanged {
+		return nil, nil
+	}
+	if err := s.writeCache(kv, etag, signature); err != nil {
+		zapx.Warnf("config http: write cache %s: %v", s.cacheFile, err)
+	}
+	return kv, nil
+}
+
+func (s *Source) verify(body []byte, signature string) error {
+	if len(s.hmacKey) == 0 {
+		return nil
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || len(sig) == 0 {
+		return ErrSignature
+	}
+	mac := hmac.New(sha256.New, s.hmacKey)
+	mac.Write(body)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return ErrSignature
+	}
+	return nil
+}
+
+// Sign returns the signature header value of body, for the config server and the tests.
+func Sign(key, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Source) formatOf(contentType string) string {
+	if s.format != "" {
+		return s.format
+	}
+	if mt, _, err := mime.ParseMediaType(contentType); err == nil {
+		switch {
+		case strings.HasSuffix(mt, "json"):
+			return "json"
+		case strings.HasSuffix(mt, "yaml"), strings.HasSuffix(mt, "yml"):
+			return "yaml"
+		}
+		// application/toml, text/x-java-properties...
+		sub := strings.TrimPrefix(mt[strings.IndexByte(mt, '/')+1:], "x-")
+		sub = strings.TrimPrefix(sub, "java-")
+		if encoding.GetCodec(sub) != nil {
+			return sub
+		}
+	}
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(strings.SplitN(s.url, "?", 2)[0]), "."))
+	if ext == "yml" {
+		return "yaml"
+	}
+	if encoding.GetCodec(ext) != nil {
+		return ext
+	}
+	return "json"
+}
+
+type cacheEntry struct {
+	URL    string `json:"url"`
+	ETag   string `json:"etag"`
+	Format string `json:"format"`
+	Data   []byte `json:"data"`
+	// Signature of Data sent by the server, checked again when the cache is read with WithHMAC
+	Signature string `json:"signature,omitempty"`
+}
+
+func (s *Source) writeCache(kv *sources.KeyValue, etag, signature string) error {
+	if s.cacheFile == "" {
+		return nil
+	}
+	data, err := json.Marshal(cacheEntry{URL: s.url, ETag: etag, Format: kv.Format, Data: kv.Value, Signature: signature})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.cacheFile), 0o700); err != nil {
+		return err
+	}
+	tmp := s.cacheFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.cacheFile)
+}
+
+func (s *Source) readCache() (*sources.KeyValue, error) {
+	if s.cacheFile == "" {
+		return nil, os.ErrNotExist
+	}
+	data, err := os.ReadFile(s.cacheFile)
+	if err != nil {
+		return nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	if entry.URL != s.url {
+		return nil, fmt.Errorf("config http: cache %s belongs to %s", s.cacheFile, entry.URL)
+	}
+	if err := s.verify(entry.Data, entry.Signature); err != nil {
+		return nil, fmt.Errorf("config http: cache %s: %w", s.cacheFile, err)
+	}
+	s.mu.Lock()
+	s.etag, s.sum = entry.ETag, sha256.Sum256(entry.Data)
+	s.mu.Unlock()
+	return &sources.KeyValue{Key: s.url, Value: entry.Data, Format: entry.Format}, nil
+}
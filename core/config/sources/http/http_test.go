@@ -0,0 +1,208 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/ini"
+	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/properties"
+	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/toml"
+)
+
+// configServer serves a versioned document, answering 304 to the current ETag.
+type configServer struct {
+	mu       sync.Mutex
+	version  int
+	body     string
+	key      []byte
+	down     bool
+	requests int32
+	notMod   int32
+}
+
+func (s *configServer) set(body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version++
+	s.body = body
+}
+
+func (s *configServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&s.requests, 1)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	etag := fmt.Sprintf(`"v%d"`, s.version)
+	if r.Header.Get("If-None-Match") == etag {
+		atomic.AddInt32(&s.notMod, 1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", "application/yaml")
+	if s.key != nil {
+		w.Header().Set(DefaultSignatureHeader, Sign(s.key, []byte(s.body)))
+	}
+	_, _ = w.Write([]byte(s.body))
+}
+
+// poll calls Next in the background.
+func poll(w sources.Watcher) <-chan []*sources.KeyValue {
+	ch := make(chan []*sources.KeyValue, 1)
+	go func() {
+		kvs, _ := w.Next()
+		ch <- kvs
+	}()
+	return ch
+}
+
+func wait(t *testing.T, ch <-chan []*sources.KeyValue) *sources.KeyValue {
+	select {
+	case kvs := <-ch:
+		if len(kvs) != 1 {
+			t.Fatalf("unexpected %v", kvs)
+		}
+		return kvs[0]
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change")
+	}
+	return nil
+}
+
+func TestSource(t *testing.T) {
+	key := []byte("shared")
+	cs := &configServer{key: key}
+	cs.set("a: 1")
+	srv := httptest.NewServer(cs)
+	defer srv.Close()
+
+	cache := filepath.Join(t.TempDir(), "cache", "config.json")
+	src := NewSource(srv.URL+"/app", WithInterval(10*time.Millisecond), WithBackoff(10*time.Millisecond, 40*time.Millisecond),
+		WithHMAC(key, ""), WithCacheFile(cache))
+	kvs, err := src.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(kvs[0].Value) != "a: 1" || kvs[0].Format != "yaml" || kvs[0].Key != srv.URL+"/app" {
+		t.Fatalf("unexpected %+v", kvs[0])
+	}
+	w, err := src.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	ch := poll(w)
+	time.Sleep(50 * time.Millisecond)
+	if atomic.LoadInt32(&cs.notMod) == 0 {
+		t.Fatal("If-None-Match not sent")
+	}
+	cs.set("a: 2")
+	if kv := wait(t, ch); string(kv.Value) != "a: 2" {
+		t.Fatalf("unexpected %s", kv.Value)
+	}
+
+	// a forged body is rejected, the next signed one is accepted
+	cs.mu.Lock()
+	cs.key = []byte("forged")
+	cs.mu.Unlock()
+	cs.set("a: 3")
+	ch = poll(w)
+	time.Sleep(50 * time.Millisecond)
+	cs.mu.Lock()
+	cs.key = key
+	cs.mu.Unlock()
+	cs.set("a: 4")
+	if kv := wait(t, ch); string(kv.Value) != "a: 4" {
+		t.Fatalf("unexpected %s", kv.Value)
+	}
+
+	// the server is down at boot: the cached copy is loaded
+	cs.mu.Lock()
+	cs.down = true
+	cs.mu.Unlock()
+	src2 := NewSource(srv.URL+"/app", WithCacheFile(cache), WithHMAC(key, ""))
+	kvs, err = src2.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(kvs[0].Value) != "a: 4" || kvs[0].Format != "yaml" {
+		t.Fatalf("unexpected %+v", kvs[0])
+	}
+	if _, err := NewSource(srv.URL + "/app").Load(); err == nil {
+		t.Fatal("want an error without cache")
+	}
+
+	// a tampered cache file is rejected
+	data, err := os.ReadFile(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatal(err)
+	}
+	entry.Data = []byte("a: 5")
+	if data, err = json.Marshal(entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cache, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewSource(srv.URL+"/app", WithCacheFile(cache), WithHMAC(key, "")).Load(); err == nil {
+		t.Fatal("want an error with a tampered cache")
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	cs := &configServer{}
+	cs.set("a: 1234567890")
+	srv := httptest.NewServer(cs)
+	defer srv.Close()
+
+	if _, err := NewSource(srv.URL+"/app", WithMaxBodySize(8)).Load(); err == nil {
+		t.Fatal("want an error for a large document")
+	}
+	if _, err := NewSource(srv.URL+"/app", WithMaxBodySize(13)).Load(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	src := NewSource("http://127.0.0.1:1/config.json", WithInterval(time.Second), WithBackoff(100*time.Millisecond, time.Second))
+	if d := src.wait(); d != time.Second {
+		t.Fatalf("want the interval, got %v", d)
+	}
+	for i, want := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		src.failures = i + 1
+		if d := src.wait(); d != want*time.Millisecond {
+			t.Errorf("failures %d: want %v, got %v", i+1, want*time.Millisecond, d)
+		}
+	}
+	if f := src.formatOf(""); f != "json" {
+		t.Errorf("want json, got %s", f)
+	}
+	for contentType, want := range map[string]string{"application/toml": "toml", "text/x-java-properties; charset=utf-8": "properties", "text/plain": "json"} {
+		if f := src.formatOf(contentType); f != want {
+			t.Errorf("%s: want %s, got %s", contentType, want, f)
+		}
+	}
+	if f := NewSource("http://config/app.ini?v=1").formatOf(""); f != "ini" {
+		t.Errorf("want ini, got %s", f)
+	}
+	if f := NewSource("http://config/app.yml").formatOf("text/plain"); f != "yaml" {
+		t.Errorf("want yaml, got %s", f)
+	}
+}
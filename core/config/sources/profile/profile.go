@@ -0,0 +1,167 @@
+// Package profile is a config source layering the profiles of a config file:
+//
+//	config.yaml         the base
+//	config_<env>.yaml   the profile, PROJECT_ENV by default
+//	config_local.yaml   the local overrides, not committed
+//
+// Each existing layer is deep merged over the previous ones: objects are merged key by key,
+// scalars and lists are replaced. The YAML tags change the merge of a node:
+//
+//	servers: !append [c]            appended to the list of the previous layers
+//	cors: !replace {enabled: false} replaces the object instead of merging it
+//	db: !include db.yaml            the content of db.yaml, relative to the including file
+//
+// The layers are loaded as one KeyValue, a reload never mixes two versions of the files.
+package profile
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+)
+
+const (
+	// LocalProfile profile of the last layer.
+	LocalProfile = "local"
+	// DefaultDebounce time waited after the last event before reloading.
+	DefaultDebounce = 100 * time.Millisecond
+)
+
+var _ sources.Source = (*profile)(nil)
+
+// Option is profile source option.
+type Option func(*profile)
+
+// WithProfile sets the profile, PROJECT_ENV by default, "" loads the base and the local layers only.
+func WithProfile(name string) Option {
+	return func(p *profile) {
+		p.name = strings.ToLower(name)
+	}
+}
+
+// WithoutLocal skips the local layer, as in production.
+func WithoutLocal() Option {
+	return func(p *profile) {
+		p.local = false
+	}
+}
+
+// WithDebounce sets the time waited after the last file event before reloading.
+func WithDebounce(d time.Duration) Option {
+	return func(p *profile) {
+		p.debounce = d
+	}
+}
+
+type profile struct {
+	path     string
+	name     string
+	local    bool
+	debounce time.Duration
+}
+
+// NewSource new a source of the layers of path.
+func NewSource(path string, opts ...Option) sources.Source {
+	p := &profile{
+		path:     path,
+		name:     strings.ToLower(os.Getenv(sources.ProjectEnvKey)),
+		local:    true,
+		debounce: DefaultDebounce,
+	}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
+}
+
+// Layers returns the files of the layers of path for the profile name, existing or not, in merge order.
+func Layers(path, name string, local bool) []string {
+	layers := []string{path}
+	if name != "" && name != LocalProfile {
+		layers = append(layers, layerName(path, name))
+	}
+	if local {
+		layers = append(layers, layerName(path, LocalProfile))
+	}
+	return layers
+}
+
+func layerName(path, name string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "_" + name + ext
+}
+
+func (p *profile) Load() ([]*sources.KeyValue, error) {
+	kv, _, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	return []*sources.KeyValue{kv}, nil
+}
+
+func (p *profile) Watch() (sources.Watcher, error) {
+	return newWatcher(p)
+}
+
+// load merges the layers, it returns the files read, includes too, to watch them.
+func (p *profile) load() (*sources.KeyValue, []string, error) {
+	var (
+		merged interface{} = map[string]interface{}{}
+		names  []string
+		files  []string
+	)
+	for i, layer := range Layers(p.path, p.name, p.local) {
+		files = append(files, layer)
+		if _, err := os.Stat(layer); i > 0 && os.IsNotExist(err) {
+			continue
+		}
+		l := &loader{}
+		v, err := l.load(layer)
+		files = append(files, l.includes...)
+		if err != nil {
+			return nil, files, err
+		}
+		if v == nil {
+			// an empty layer
+			names = append(names, filepath.Base(layer))
+			continue
+		}
+		if _, ok := v.(map[string]interface{}); !ok {
+			return nil, files, fmt.Errorf("profile: %s is not an object", layer)
+		}
+		merged = merge(merged, v)
+		names = append(names, filepath.Base(layer))
+	}
+	data, err := json.Marshal(finalize(merged))
+	if err != nil {
+		return nil, files, err
+	}
+	return &sources.KeyValue{
+		Key:      strings.Join(names, "+"),
+		Value:    data,
+		Format:   "json",
+		Priority: sources.PriorityFile,
+	}, files, nil
+}
+
+// Resolve returns the merged config of path for the profile name, as the source loads it.
+func Resolve(path, name string, local bool) (map[string]interface{}, error) {
+	p := &profile{path: path, name: strings.ToLower(name), local: local}
+	kv, _, err := p.load()
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	err = json.Unmarshal(kv.Value, &m)
+	return m, err
+}
+
+func checksum(kv *sources.KeyValue) [sha256.Size]byte {
+	return sha256.Sum256(append([]byte(kv.Key+"\x00"), kv.Value...))
+}
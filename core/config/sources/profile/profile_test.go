@@ -0,0 +1,145 @@
+package profile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+)
+
+func write(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func toJSON(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, map[string]string{
+		"config.yaml": `
+defaults: &defaults
+  timeout: 3s
+  retries: 2
+server:
+  <<: *defaults
+  port: 8080
+  hosts: [a, b]
+  cors: {enabled: true, origins: ["*"]}
+db: !include conf/db.yaml
+`,
+		"conf/db.yaml":      "host: localhost\nuser: !include user.yaml\n",
+		"conf/user.yaml":    "root\n",
+		"config_prod.yaml":  "server:\n  port: 80\n  hosts: !append [c]\n  cors: !replace {enabled: false}\n",
+		"config_local.yaml": "db:\n  host: 127.0.0.1\n",
+	})
+	path := filepath.Join(dir, "config.yaml")
+
+	got, err := Resolve(path, "PROD", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"db":{"host":"127.0.0.1","user":"root"},"defaults":{"retries":2,"timeout":"3s"},` +
+		`"server":{"cors":{"enabled":false},"hosts":["a","b","c"],"port":80,"retries":2,"timeout":"3s"}}`
+	if toJSON(got) != want {
+		t.Fatalf("want %s, got %s", want, toJSON(got))
+	}
+
+	// a missing profile is skipped, the local layer too when disabled
+	got, err = Resolve(path, "dev", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if toJSON(got["db"]) != `{"host":"localhost","user":"root"}` || toJSON(got["server"].(map[string]interface{})["hosts"]) != `["a","b"]` {
+		t.Fatalf("unexpected %s", toJSON(got))
+	}
+
+	kvs, err := NewSource(path, WithProfile("prod")).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kvs[0].Key != "config.yaml+config_prod.yaml+config_local.yaml" || kvs[0].Format != "json" || kvs[0].Priority != sources.PriorityFile {
+		t.Fatalf("unexpected %+v", kvs[0])
+	}
+}
+
+func TestResolveErrors(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, map[string]string{
+		"config.yaml": "a: !include b.yaml\n",
+		"b.yaml":      "b: !include config.yaml\n",
+		"list.yaml":   "a: !append {b: 1}\n",
+	})
+	if _, err := Resolve(filepath.Join(dir, "config.yaml"), "", false); err == nil || !strings.Contains(err.Error(), "include cycle") {
+		t.Fatalf("want an include cycle, got %v", err)
+	}
+	if _, err := Resolve(filepath.Join(dir, "list.yaml"), "", false); err == nil || !strings.Contains(err.Error(), "!append takes a list") {
+		t.Fatalf("want a list error, got %v", err)
+	}
+	if _, err := Resolve(filepath.Join(dir, "missing.yaml"), "", false); !os.IsNotExist(err) {
+		t.Fatalf("want the base to be required, got %v", err)
+	}
+}
+
+func TestLayers(t *testing.T) {
+	want := []string{"conf/app.yaml", "conf/app_test.yaml", "conf/app_local.yaml"}
+	if got := Layers("conf/app.yaml", "test", true); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	if got := Layers("app.yaml", LocalProfile, false); !reflect.DeepEqual([]string{"app.yaml"}, got) {
+		t.Fatalf("unexpected %v", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	write(t, dir, map[string]string{"config.yaml": "a: 1\ndb: !include db.yaml\n", "db.yaml": "host: a\n"})
+	src := NewSource(filepath.Join(dir, "config.yaml"), WithProfile(""), WithDebounce(50*time.Millisecond))
+	w, err := src.Watch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	next := func() (string, bool) {
+		ch := make(chan string, 1)
+		go func() {
+			kvs, err := w.Next()
+			if err == nil {
+				ch <- string(kvs[0].Value)
+			}
+		}()
+		select {
+		case v := <-ch:
+			return v, true
+		case <-time.After(2 * time.Second):
+			return "", false
+		}
+	}
+
+	// a change of an include is a reload
+	write(t, dir, map[string]string{"db.yaml": "host: b\n"})
+	if v, ok := next(); !ok || v != `{"a":1,"db":{"host":"b"}}` {
+		t.Fatalf("unexpected %q %v", v, ok)
+	}
+	// the local layer is created
+	write(t, dir, map[string]string{"config_local.yaml": "a: 2\n"})
+	if v, ok := next(); !ok || v != `{"a":2,"db":{"host":"b"}}` {
+		t.Fatalf("unexpected %q %v", v, ok)
+	}
+}
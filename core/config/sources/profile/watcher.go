@@ -0,0 +1,103 @@
+package profile
+
+import (
+	"context"
+	"crypto/sha256"
+	"path/filepath"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+type watcher struct {
+	p  *profile
+	fw *fsnotify.Watcher
+	// files layers and includes, the events of the other files of their directories are ignored
+	files map[string]bool
+	last  [sha256.Size]byte
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var _ sources.Watcher = (*watcher)(nil)
+
+func newWatcher(p *profile) (sources.Watcher, error) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	w := &watcher{p: p, fw: fw}
+	kv, files, err := p.load()
+	if err == nil {
+		w.last = checksum(kv)
+	}
+	// the directories are watched: a missing layer may be created, editors replace the files they save
+	if err := w.track(files); err != nil {
+		fw.Close()
+		return nil, err
+	}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	return w, nil
+}
+
+func (w *watcher) track(files []string) error {
+	w.files = make(map[string]bool, len(files))
+	for _, f := range files {
+		abs, err := filepath.Abs(f)
+		if err != nil {
+			return err
+		}
+		w.files[abs] = true
+		if err := w.fw.Add(filepath.Dir(abs)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Next returns the merged layers once the events stopped for the debounce time and the result changed.
+func (w *watcher) Next() ([]*sources.KeyValue, error) {
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case event, ok := <-w.fw.Events:
+			if !ok {
+				return nil, context.Canceled
+			}
+			if event.Op == fsnotify.Chmod || !w.files[filepath.Clean(event.Name)] {
+				continue
+			}
+			timer.Reset(w.p.debounce)
+		case <-timer.C:
+			kv, files, err := w.p.load()
+			// the includes may have changed, even when the files do not parse
+			if terr := w.track(files); terr != nil && err == nil {
+				err = terr
+			}
+			if err != nil {
+				return nil, err
+			}
+			if sum := checksum(kv); sum != w.last {
+				w.last = sum
+				return []*sources.KeyValue{kv}, nil
+			}
+		case err, ok := <-w.fw.Errors:
+			if !ok {
+				return nil, context.Canceled
+			}
+			return nil, err
+		}
+	}
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	return w.fw.Close()
+}
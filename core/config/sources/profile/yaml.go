@@ -0,0 +1,187 @@
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML tags of the merge directives.
+const (
+	TagInclude = "!include"
+	TagAppend  = "!append"
+	TagReplace = "!replace"
+)
+
+// appendList a list appended to the list of the previous layers.
+type appendList []interface{}
+
+// replaced a node replacing the node of the previous layers instead of being merged.
+type replaced struct {
+	value interface{}
+}
+
+// loader decodes a file, following the includes.
+type loader struct {
+	// stack files being loaded, to detect include cycles
+	stack    []string
+	includes []string
+}
+
+func (l *loader) load(path string) (interface{}, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range l.stack {
+		if p == abs {
+			return nil, fmt.Errorf("profile: include cycle %s -> %s", strings.Join(l.stack, " -> "), abs)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("profile: %s: %w", path, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	l.stack = append(l.stack, abs)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+	v, err := l.convert(doc.Content[0], filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("profile: %s: %w", path, err)
+	}
+	return v, nil
+}
+
+// convert turns node into the values of encoding/json, applying the directives.
+func (l *loader) convert(node *yaml.Node, dir string) (interface{}, error) {
+	switch node.Tag {
+	case TagInclude:
+		if node.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("line %d: %s takes a file name", node.Line, TagInclude)
+		}
+		path := node.Value
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		l.includes = append(l.includes, path)
+		return l.load(path)
+	case TagAppend, TagReplace:
+		tag := node.Tag
+		plain := *node
+		plain.Tag = ""
+		v, err := l.convert(&plain, dir)
+		if err != nil {
+			return nil, err
+		}
+		if tag == TagReplace {
+			return replaced{value: v}, nil
+		}
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("line %d: %s takes a list", node.Line, TagAppend)
+		}
+		return appendList(list), nil
+	}
+
+	switch node.Kind {
+	case yaml.AliasNode:
+		return l.convert(node.Alias, dir)
+	case yaml.MappingNode:
+		m := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			v, err := l.convert(value, dir)
+			if err != nil {
+				return nil, err
+			}
+			if key.Tag == "!!merge" {
+				// <<: *anchor, the keys of the mapping win
+				if base, ok := v.(map[string]interface{}); ok {
+					for k, bv := range base {
+						if _, exists := m[k]; !exists {
+							m[k] = bv
+						}
+					}
+				}
+				continue
+			}
+			m[key.Value] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		list := make([]interface{}, 0, len(node.Content))
+		for _, item := range node.Content {
+			v, err := l.convert(item, dir)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, finalize(v))
+		}
+		return list, nil
+	}
+	var v interface{}
+	if err := node.Decode(&v); err != nil {
+		return nil, fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	return v, nil
+}
+
+// merge merges src over dst.
+func merge(dst, src interface{}) interface{} {
+	switch s := src.(type) {
+	case replaced:
+		return finalize(s.value)
+	case appendList:
+		switch d := dst.(type) {
+		case []interface{}:
+			return append(append([]interface{}{}, d...), s...)
+		case appendList:
+			return append(append(appendList{}, d...), s...)
+		}
+		return s
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			return src
+		}
+		res := make(map[string]interface{}, len(d)+len(s))
+		for k, v := range d {
+			res[k] = v
+		}
+		for k, v := range s {
+			res[k] = merge(res[k], v)
+		}
+		return res
+	}
+	return src
+}
+
+// finalize removes the directives left in v.
+func finalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case replaced:
+		return finalize(t.value)
+	case appendList:
+		return finalize([]interface{}(t))
+	case []interface{}:
+		for i, item := range t {
+			t[i] = finalize(item)
+		}
+		return t
+	case map[string]interface{}:
+		for k, item := range t {
+			t[k] = finalize(item)
+		}
+		return t
+	}
+	return v
+}
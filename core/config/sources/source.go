@@ -1,5 +1,7 @@
 package sources
 
+import "strconv"
+
 const (
 	//ProjectEnvKey recognize env of project, dev, release, production
 	ProjectEnvKey = "PROJECT_ENV"
@@ -9,11 +11,39 @@ const (
 	DefaultPrefix = "config"
 )
 
+// Priority of the sources, a value never overrides the value of a source with a higher priority,
+// sources of the same priority override in load order.
+const (
+	PriorityDefault = -100
+	// PriorityFile the zero value, sources not setting a priority are files
+	PriorityFile = 0
+	PriorityEnv  = 100
+	PriorityFlag = 200
+)
+
+// PriorityName names the priority p.
+func PriorityName(p int) string {
+	switch p {
+	case PriorityDefault:
+		return "default"
+	case PriorityFile:
+		return "file"
+	case PriorityEnv:
+		return "env"
+	case PriorityFlag:
+		return "flag"
+	}
+	return strconv.Itoa(p)
+}
+
 // KeyValue is config key value.
 type KeyValue struct {
-	Key    string
-	Value  []byte
-	Format string
+	Key      string
+	Value    []byte
+	Format   string
+	Priority int
+	// Sensitive values, e.g. the files of a mounted secret, are never logged
+	Sensitive bool
 }
 
 // Source is config source.
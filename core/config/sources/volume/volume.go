@@ -0,0 +1,147 @@
+// Package volume is a config source of a Kubernetes ConfigMap or Secret volume.
+//
+// Kubelet writes a new version of the volume into a hidden timestamped directory then swaps the ..data symlink to it,
+// the visible files are symlinks through ..data. The source reads the whole directory behind ..data at once,
+// so a reload never mixes two versions, and skips the hidden entries.
+// Directories without ..data are read as they are.
+package volume
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+	"github.com/kweaver-ai/idrm-go-frame/core/encoding"
+)
+
+const (
+	// dataLink symlink kubelet swaps to the current version of the volume
+	dataLink = "..data"
+	// DefaultDebounce time waited after the last event before reloading.
+	DefaultDebounce = 200 * time.Millisecond
+
+	maxReadAttempts = 3
+)
+
+var _ sources.Source = (*volume)(nil)
+
+// Option is volume source option.
+type Option func(*volume)
+
+// WithDebounce sets the time waited after the last event of a burst before reloading.
+func WithDebounce(d time.Duration) Option {
+	return func(v *volume) {
+		v.debounce = d
+	}
+}
+
+type volume struct {
+	dir      string
+	debounce time.Duration
+}
+
+// NewSource new a source of the volume mounted at dir.
+// Every visible file is a KeyValue named after the file, its extension is the format when a codec
+// is registered for it; other files, as the keys of a Secret or tls.crt, are stored as {name: content}.
+func NewSource(dir string, opts ...Option) sources.Source {
+	v := &volume{dir: dir, debounce: DefaultDebounce}
+	for _, o := range opts {
+		o(v)
+	}
+	return v
+}
+
+func (v *volume) Load() ([]*sources.KeyValue, error) {
+	return v.load()
+}
+
+func (v *volume) Watch() (sources.Watcher, error) {
+	return newWatcher(v)
+}
+
+// load reads a consistent version of the volume, it reads again when ..data is swapped during the read.
+func (v *volume) load() ([]*sources.KeyValue, error) {
+	for i := 0; i < maxReadAttempts; i++ {
+		target, linked := v.dataDir()
+		if !linked {
+			return readDir(v.dir)
+		}
+		kvs, err := readDir(target)
+		if after, _ := v.dataDir(); after != target {
+			continue
+		}
+		return kvs, err
+	}
+	return nil, fmt.Errorf("volume: %s changed during %d reads", v.dir, maxReadAttempts)
+}
+
+// dataDir returns the directory ..data points to.
+func (v *volume) dataDir() (string, bool) {
+	target, err := filepath.EvalSymlinks(filepath.Join(v.dir, dataLink))
+	if err != nil {
+		return "", false
+	}
+	return target, true
+}
+
+func readDir(dir string) ([]*sources.KeyValue, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var kvs []*sources.KeyValue
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		// stat follows the symlinks of the visible files
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if fi.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, &sources.KeyValue{Key: name, Value: data, Format: format(name), Priority: sources.PriorityFile, Sensitive: true})
+	}
+	return kvs, nil
+}
+
+// format returns the extension of name if it has a codec, "" reads the file as raw content.
+func format(name string) string {
+	idx := strings.LastIndex(name, ".")
+	if idx <= 0 {
+		return ""
+	}
+	ext := strings.ToLower(name[idx+1:])
+	if ext == "yml" {
+		return "yaml"
+	}
+	if encoding.GetCodec(ext) == nil {
+		return ""
+	}
+	return ext
+}
+
+// checksum identifies a version of the volume.
+func checksum(kvs []*sources.KeyValue) string {
+	sorted := append([]*sources.KeyValue{}, kvs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Key < sorted[j].Key })
+	h := sha256.New()
+	for _, kv := range sorted {
+		fmt.Fprintf(h, "%s\x00%d\x00", kv.Key, len(kv.Value))
+		h.Write(kv.Value)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
@@ -0,0 +1,90 @@
+package volume
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+type watcher struct {
+	v    *volume
+	fw   *fsnotify.Watcher
+	last string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+var _ sources.Watcher = (*watcher)(nil)
+
+func newWatcher(v *volume) (sources.Watcher, error) {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	// the directory is watched, the events of the files behind the symlinks are not reported
+	if err := fw.Add(v.dir); err != nil {
+		fw.Close()
+		return nil, err
+	}
+	w := &watcher{v: v, fw: fw}
+	if kvs, err := v.load(); err == nil {
+		w.last = checksum(kvs)
+	}
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	return w, nil
+}
+
+// Next returns the whole volume once the events stopped for the debounce time and the content changed.
+func (w *watcher) Next() ([]*sources.KeyValue, error) {
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return nil, w.ctx.Err()
+		case event, ok := <-w.fw.Events:
+			if !ok {
+				return nil, context.Canceled
+			}
+			if !relevant(event) {
+				continue
+			}
+			timer.Reset(w.v.debounce)
+		case <-timer.C:
+			kvs, err := w.v.load()
+			if err != nil {
+				return nil, err
+			}
+			if sum := checksum(kvs); sum != w.last {
+				w.last = sum
+				return kvs, nil
+			}
+		case err, ok := <-w.fw.Errors:
+			if !ok {
+				return nil, context.Canceled
+			}
+			return nil, err
+		}
+	}
+}
+
+// relevant reports whether event may change the volume: the swap of ..data or a visible file written in place.
+func relevant(event fsnotify.Event) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	name := filepath.Base(event.Name)
+	return name == dataLink || !strings.HasPrefix(name, ".")
+}
+
+func (w *watcher) Stop() error {
+	w.cancel()
+	return w.fw.Close()
+}
@@ -34,6 +34,8 @@ type Value interface {
 
 type atomicValue struct {
 	atomic.Value
+	// path config key of the value, prefixes the errors of Scan
+	path string
 }
 
 func (v *atomicValue) Bool() (bool, error) {
@@ -81,8 +83,8 @@ func (v *atomicValue) Int() (int64, error) {
 func (v *atomicValue) Slice() ([]Value, error) {
 	if vals, ok := v.Load().([]interface{}); ok {
 		var slices []Value
-		for _, val := range vals {
-			a := &atomicValue{}
+		for i, val := range vals {
+			a := &atomicValue{path: fmt.Sprintf("%s[%d]", v.path, i)}
 			a.Store(val)
 			slices = append(slices, a)
 		}
@@ -95,7 +97,7 @@ func (v *atomicValue) Map() (map[string]Value, error) {
 	if vals, ok := v.Load().(map[string]interface{}); ok {
 		m := make(map[string]Value)
 		for key, val := range vals {
-			a := &atomicValue{}
+			a := &atomicValue{path: joinPath(v.path, key)}
 			a.Store(val)
 			m[key] = a
 		}
@@ -168,7 +170,7 @@ func (v *atomicValue) Scan(obj interface{}) error {
 	if pb, ok := obj.(proto.Message); ok {
 		return json.UnmarshalOptions.Unmarshal(data, pb)
 	}
-	return stdjson.Unmarshal(data, obj)
+	return scanJSON(data, obj, v.path)
 }
 
 type errValue struct {
@@ -0,0 +1,156 @@
+// Package ini is a codec of the ini files.
+//
+// The keys before the first section are top level keys, the keys of "[db.master]" are nested
+// in {"db": {"master": {...}}}. The dotted keys and the lists are written as in the properties files:
+// "hosts[0] = a". Lines starting with ';' or '#' are comments, as the text after " ;" or " #"
+// in an unquoted value. The values are strings, as in the properties codec.
+package ini
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/encoding"
+	"github.com/kweaver-ai/idrm-go-frame/core/internal/keypath"
+)
+
+// Name is the name registered for the ini codec.
+const Name = "ini"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec is a Codec implementation with the ini format.
+type codec struct{}
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	m, err := keypath.ToMap(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	writeSection(&buf, "", m)
+	return buf.Bytes(), nil
+}
+
+// writeSection writes the leaves of m, then the sections of its nested objects.
+func writeSection(buf *bytes.Buffer, name string, m map[string]interface{}) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sections []string
+	if name != "" {
+		if buf.Len() > 0 {
+			buf.WriteByte('\n')
+		}
+		fmt.Fprintf(buf, "[%s]\n", name)
+	}
+	for _, k := range keys {
+		if _, ok := m[k].(map[string]interface{}); ok {
+			sections = append(sections, k)
+			continue
+		}
+		keypath.Walk(k, m[k], func(key string, v interface{}) {
+			text, quoted := keypath.Format(v)
+			if quoted || strings.ContainsAny(text, ";#\n") {
+				text = fmt.Sprintf("%q", text)
+			}
+			fmt.Fprintf(buf, "%s = %s\n", key, text)
+		})
+	}
+	for _, k := range sections {
+		sub := k
+		if name != "" {
+			sub = name + "." + k
+		}
+		writeSection(buf, sub, m[k].(map[string]interface{}))
+	}
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	m := make(map[string]interface{})
+	section := ""
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return fmt.Errorf("ini: line %d: unterminated section %q", i+1, line)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if _, exists := lookup(m, section); !exists && section != "" {
+				if err := keypath.Set(m, section, map[string]interface{}{}); err != nil {
+					return fmt.Errorf("ini: line %d: %w", i+1, err)
+				}
+			}
+			continue
+		}
+		key, value := line, ""
+		if idx := strings.IndexAny(line, "=:"); idx >= 0 {
+			key, value = strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:])
+		}
+		if key == "" {
+			return fmt.Errorf("ini: line %d: missing key", i+1)
+		}
+		if section != "" {
+			key = section + "." + key
+		}
+		if err := keypath.Set(m, key, parseValue(value)); err != nil {
+			return fmt.Errorf("ini: line %d: %w", i+1, err)
+		}
+	}
+	return keypath.Decode(keypath.Lists(m).(map[string]interface{}), v)
+}
+
+func (codec) Name() string {
+	return Name
+}
+
+// lookup reports whether the object at the dotted path exists.
+func lookup(m map[string]interface{}, path string) (map[string]interface{}, bool) {
+	for _, k := range strings.Split(path, ".") {
+		sub, ok := m[k].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		m = sub
+	}
+	return m, true
+}
+
+// parseValue returns the string of a quoted value, Go escapes are allowed in double quotes.
+func parseValue(value string) string {
+	if len(value) >= 2 && value[0] == '\'' {
+		if end := strings.IndexByte(value[1:], '\''); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if len(value) >= 2 && value[0] == '"' {
+		for end := 1; end < len(value); end++ {
+			if value[end] == '\\' {
+				end++
+				continue
+			}
+			if value[end] == '"' {
+				if s, err := strconv.Unquote(value[:end+1]); err == nil {
+					return s
+				}
+				return value[1:end]
+			}
+		}
+	}
+	for _, marker := range []string{" ;", " #", "\t;", "\t#"} {
+		if idx := strings.Index(value, marker); idx >= 0 {
+			value = strings.TrimSpace(value[:idx])
+		}
+	}
+	return value
+}
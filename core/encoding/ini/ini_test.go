@@ -0,0 +1,82 @@
+package ini
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/encoding"
+)
+
+func TestCodec_Unmarshal(t *testing.T) {
+	data := `; global
+name = order
+debug = true
+
+[db.master]
+host = localhost ; inline comment
+port: 3306
+password = "12#34"
+dsn = 'root:pw@tcp(db)/x?a=1'
+
+[kafka]
+brokers[0] = a:9092
+brokers[1] = b:9092
+`
+	var got map[string]interface{}
+	if err := (codec{}).Unmarshal([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"name":  "order",
+		"debug": "true",
+		"db": map[string]interface{}{"master": map[string]interface{}{
+			"host": "localhost", "port": "3306", "password": "12#34", "dsn": "root:pw@tcp(db)/x?a=1",
+		}},
+		"kafka": map[string]interface{}{"brokers": []interface{}{"a:9092", "b:9092"}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	var conf struct {
+		Debug bool `json:"debug"`
+		DB    struct {
+			Master struct {
+				Port     uint16 `json:"port"`
+				Password string `json:"password"`
+			} `json:"master"`
+		} `json:"db"`
+	}
+	if err := (codec{}).Unmarshal([]byte(data), &conf); err != nil {
+		t.Fatal(err)
+	}
+	if !conf.Debug || conf.DB.Master.Port != 3306 || conf.DB.Master.Password != "12#34" {
+		t.Fatalf("unexpected %+v", conf)
+	}
+	if err := (codec{}).Unmarshal([]byte("[db\nhost=a"), &got); err == nil {
+		t.Fatal("want an unterminated section error")
+	}
+}
+
+func TestCodec_Marshal(t *testing.T) {
+	v := map[string]interface{}{
+		"name": "order",
+		"db":   map[string]interface{}{"master": map[string]interface{}{"host": "localhost", "port": 3306}, "pool": 10},
+		"tags": []interface{}{"a", "b; c"},
+	}
+	data, err := (codec{}).Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "name = order\ntags[0] = a\ntags[1] = \"b; c\"\n\n[db]\npool = 10\n\n[db.master]\nhost = localhost\nport = 3306\n"
+	if string(data) != want {
+		t.Fatalf("want %q, got %q", want, data)
+	}
+	var back map[string]interface{}
+	if err := (codec{}).Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back["tags"].([]interface{})[1] != "b; c" || encoding.GetCodec(Name) == nil {
+		t.Fatalf("unexpected round trip %v", back)
+	}
+}
@@ -0,0 +1,187 @@
+// Package properties is a codec of the Java .properties files.
+//
+// The dotted keys are nested: "db.host=a" is {"db": {"host": "a"}}, "hosts[0]=a" the first item of the list hosts.
+// The values are strings, enclosed in double quotes to keep their surrounding spaces; decoded into a struct,
+// the strings of its bool and number fields are converted.
+package properties
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/encoding"
+	"github.com/kweaver-ai/idrm-go-frame/core/internal/keypath"
+)
+
+// Name is the name registered for the properties codec.
+const Name = "properties"
+
+func init() {
+	encoding.RegisterCodec(codec{})
+}
+
+// codec is a Codec implementation with the properties format.
+type codec struct{}
+
+func (codec) Marshal(v interface{}) ([]byte, error) {
+	m, err := keypath.ToMap(v)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	keypath.Walk("", m, func(key string, v interface{}) {
+		text, quoted := keypath.Format(v)
+		if quoted {
+			text = `"` + text + `"`
+		}
+		buf.WriteString(escape(key, true))
+		buf.WriteByte('=')
+		buf.WriteString(escape(text, false))
+		buf.WriteByte('\n')
+	})
+	return buf.Bytes(), nil
+}
+
+func (codec) Unmarshal(data []byte, v interface{}) error {
+	m := make(map[string]interface{})
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	var (
+		logical strings.Builder
+		start   int
+	)
+	for i, text := range lines {
+		// the leading spaces of the continuation lines are skipped too
+		text = strings.TrimLeft(text, " \t\f")
+		if logical.Len() == 0 {
+			if text == "" || text[0] == '#' || text[0] == '!' {
+				continue
+			}
+			start = i + 1
+		}
+		if continued(text) && i < len(lines)-1 {
+			logical.WriteString(text[:len(text)-1])
+			continue
+		}
+		logical.WriteString(text)
+		key, value, err := parseLine(logical.String())
+		logical.Reset()
+		if err == nil {
+			err = keypath.Set(m, key, value)
+		}
+		if err != nil {
+			return fmt.Errorf("properties: line %d: %w", start, err)
+		}
+	}
+	return keypath.Decode(keypath.Lists(m).(map[string]interface{}), v)
+}
+
+func (codec) Name() string {
+	return Name
+}
+
+// continued reports whether line ends with an odd number of backslashes.
+func continued(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// parseLine splits a logical line at the first unescaped '=', ':' or white space.
+func parseLine(line string) (string, string, error) {
+	end := len(line)
+	for i := 0; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			end = i
+			break
+		}
+	}
+	rest := strings.TrimLeft(line[end:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	key, err := unescape(line[:end])
+	if err != nil {
+		return "", "", err
+	}
+	quoted := keypath.Quoted(rest)
+	value, err := unescape(rest)
+	if err != nil {
+		return "", "", err
+	}
+	if quoted {
+		return key, value[1 : len(value)-1], nil
+	}
+	return key, value, nil
+}
+
+func unescape(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' || i == len(s)-1 {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("malformed \\u escape in %q", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("malformed \\u escape in %q", s)
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+func escape(s string, key bool) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '=', ':', ' ', '#', '!':
+			if key || (r == ' ' && i == 0) {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
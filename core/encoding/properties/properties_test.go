@@ -0,0 +1,99 @@
+package properties
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/encoding"
+)
+
+func TestCodec_Unmarshal(t *testing.T) {
+	data := `# comment
+! comment too
+db.host = localhost
+db.port: 3306
+db.password="123456"
+db.pool.enabled true
+kafka.brokers[0]=a:9092
+kafka.brokers[1]=b:9092
+app.name=order \
+    service
+app.zip=007
+app.ratio=0.5
+app.greeting=\u4f60\u597d
+path\ with\ spaces=C:\\data
+empty=
+`
+	var got map[string]interface{}
+	if err := (codec{}).Unmarshal([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"db": map[string]interface{}{
+			"host": "localhost", "port": "3306", "password": "123456",
+			"pool": map[string]interface{}{"enabled": "true"},
+		},
+		"kafka":            map[string]interface{}{"brokers": []interface{}{"a:9092", "b:9092"}},
+		"app":              map[string]interface{}{"name": "order service", "zip": "007", "ratio": "0.5", "greeting": "你好"},
+		"path with spaces": `C:\data`,
+		"empty":            "",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	var conf struct {
+		DB struct {
+			Port     int    `json:"port"`
+			Password string `json:"password"`
+			Pool     struct {
+				Enabled bool
+			} `json:"pool"`
+		} `json:"db"`
+		App struct {
+			Zip   string  `json:"zip"`
+			Ratio float64 `json:"ratio"`
+		} `json:"app"`
+	}
+	if err := (codec{}).Unmarshal([]byte(data), &conf); err != nil {
+		t.Fatal(err)
+	}
+	if conf.DB.Port != 3306 || conf.DB.Password != "123456" || !conf.DB.Pool.Enabled || conf.App.Zip != "007" || conf.App.Ratio != 0.5 {
+		t.Fatalf("unexpected %+v", conf)
+	}
+	var version struct {
+		Version string `json:"version"`
+	}
+	if err := (codec{}).Unmarshal([]byte("version=1.10"), &version); err != nil || version.Version != "1.10" {
+		t.Fatalf("unexpected %+v %v", version, err)
+	}
+	if err := (codec{}).Unmarshal([]byte("a=1\na.b=2"), &got); err == nil {
+		t.Fatal("want a conflict error")
+	}
+}
+
+func TestCodec_Marshal(t *testing.T) {
+	v := map[string]interface{}{
+		"db":    map[string]interface{}{"host": "localhost", "port": 3306, "password": "123456"},
+		"hosts": []interface{}{"a", "b"},
+		"key=1": " padded",
+	}
+	data, err := (codec{}).Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "db.host=localhost\ndb.password=123456\ndb.port=3306\nhosts[0]=a\nhosts[1]=b\nkey\\=1=\" padded\"\n"
+	if string(data) != want {
+		t.Fatalf("want %q, got %q", want, data)
+	}
+	var back map[string]interface{}
+	if err := (codec{}).Unmarshal(data, &back); err != nil {
+		t.Fatal(err)
+	}
+	if back["db"].(map[string]interface{})["password"] != "123456" || back["key=1"] != " padded" {
+		t.Fatalf("unexpected round trip %v", back)
+	}
+	if encoding.GetCodec(Name) == nil {
+		t.Fatal("codec not registered")
+	}
+}
@@ -0,0 +1,37 @@
+package toml
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/encoding"
+)
+
+func TestCodec(t *testing.T) {
+	data := `name = "order"
+
+[db]
+host = "localhost"
+port = 3306
+hosts = ["a", "b"]
+`
+	var got map[string]interface{}
+	if err := (codec{}).Unmarshal([]byte(data), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"name": "order",
+		"db":   map[string]interface{}{"host": "localhost", "port": int64(3306), "hosts": []interface{}{"a", "b"}},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	out, err := (codec{}).Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back map[string]interface{}
+	if err := encoding.GetCodec(Name).Unmarshal(out, &back); err != nil || !reflect.DeepEqual(want, back) {
+		t.Fatalf("unexpected round trip %s: %v", out, err)
+	}
+}
@@ -1,6 +1,7 @@
 package enum
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"reflect"
@@ -8,6 +9,8 @@ import (
 	"strconv"
 	"strings"
 	"unsafe"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/i18n"
 )
 
 // IntegerType 整形数据 int，为什么这么大，因为有时候，项目会使用位计算
@@ -365,3 +368,18 @@ func TypeDict[P enumProperty](s, d string, key P) *Object {
 	}
 	return Query(d, source.String)
 }
+
+// LocalDisplay returns the Display of the enum value v in the locale of ctx, looked up in the
+// i18n catalogs under "enum.<TypeName>.<String>", the registered Display when it is missing.
+func LocalDisplay[T any](ctx context.Context, v T) string {
+	obj := (*Object)(unsafe.Pointer(&v))
+	return DisplayOf(ctx, ObjectName[T](), *obj)
+}
+
+// DisplayOf is LocalDisplay for objects returned by Query and Objs, name is the enum type name.
+func DisplayOf(ctx context.Context, name string, obj Object) string {
+	if display, ok := i18n.Default().Lookup(i18n.FromContext(ctx), "enum."+name+"."+obj.String); ok {
+		return display
+	}
+	return obj.Display
+}
@@ -1,5 +1,7 @@
 package agcodes
 
+import "net/http"
+
 type Coder interface {
     GetErrorCode() string
     GetDescription() string
@@ -9,6 +11,37 @@ type Coder interface {
     GetErrorLink() string
 }
 
+// ArgsCoder is a Coder whose description was formatted with args.
+type ArgsCoder interface {
+    Coder
+    GetArgs() []interface{}
+}
+
+// StatusCoder is a Coder carrying the HTTP status and the gRPC code it is transported with.
+type StatusCoder interface {
+    Coder
+    GetHTTPStatus() int
+    // GetGRPCCode returns the google.golang.org/grpc/codes value.
+    GetGRPCCode() uint32
+}
+
+// gRPC codes of google.golang.org/grpc/codes, the module does not depend on grpc.
+const (
+    GRPCOK                 uint32 = 0
+    GRPCUnknown            uint32 = 2
+    GRPCInvalidArgument    uint32 = 3
+    GRPCDeadlineExceeded   uint32 = 4
+    GRPCNotFound           uint32 = 5
+    GRPCAlreadyExists      uint32 = 6
+    GRPCPermissionDenied   uint32 = 7
+    GRPCFailedPrecondition uint32 = 9
+    GRPCAborted            uint32 = 10
+    GRPCUnimplemented      uint32 = 12
+    GRPCInternal           uint32 = 13
+    GRPCUnavailable        uint32 = 14
+    GRPCUnauthenticated    uint32 = 16
+)
+
 // New creates and returns an error code.
 func New(errorCode, description, cause, solution string, detail interface{}, errLink string) Coder {
 
@@ -24,13 +57,75 @@ func New(errorCode, description, cause, solution string, detail interface{}, err
 
 // WithCode creates and returns a new error code based on given Code.
 func WithCode(code Coder, detail interface{}) Coder {
+    c := clone(code)
+    c.ErrorDetails = detail
+    return c
+}
 
-    return localCoder{
+// WithArgs returns code remembering the args its description was formatted with,
+// so that a translated description can be formatted again.
+func WithArgs(code Coder, args ...interface{}) Coder {
+    c := clone(code)
+    c.args = joinArgs(args)
+    return c
+}
+
+// WithStatus returns code transported with httpStatus and grpcCode, zero keeps the current value.
+func WithStatus(code Coder, httpStatus int, grpcCode uint32) Coder {
+    c := clone(code)
+    if httpStatus != 0 {
+        c.HttpStatus = httpStatus
+    }
+    if grpcCode != 0 {
+        c.GrpcCode = grpcCode
+    }
+    return c
+}
+
+// HTTPStatus returns the HTTP status of code, http.StatusInternalServerError when it has none.
+func HTTPStatus(code Coder) int {
+    if c, ok := code.(StatusCoder); ok && c.GetHTTPStatus() != 0 {
+        return c.GetHTTPStatus()
+    }
+    if code != nil && code.GetErrorCode() == CodeOK.ErrorCode {
+        return http.StatusOK
+    }
+    return http.StatusInternalServerError
+}
+
+// GRPCCode returns the gRPC code of code, GRPCInternal when it has none.
+func GRPCCode(code Coder) uint32 {
+    if c, ok := code.(StatusCoder); ok && c.GetGRPCCode() != 0 {
+        return c.GetGRPCCode()
+    }
+    if code != nil && code.GetErrorCode() == CodeOK.ErrorCode {
+        return GRPCOK
+    }
+    return GRPCInternal
+}
+
+// clone copies code, including its args and status, into a localCoder.
+func clone(code Coder) localCoder {
+    c := localCoder{
         ErrorCode:    code.GetErrorCode(),
         Description:  code.GetDescription(),
         Cause:        code.GetCause(),
         Solution:     code.GetSolution(),
-        ErrorDetails: detail,
+        ErrorDetails: code.GetErrorDetails(),
         ErrorLink:    code.GetErrorLink(),
+        args:         joinArgs(Args(code)),
+    }
+    if sc, ok := code.(StatusCoder); ok {
+        c.HttpStatus = sc.GetHTTPStatus()
+        c.GrpcCode = sc.GetGRPCCode()
+    }
+    return c
+}
+
+// Args returns the args of an ArgsCoder, nil for other coders.
+func Args(code Coder) []interface{} {
+    if c, ok := code.(ArgsCoder); ok {
+        return c.GetArgs()
     }
+    return nil
 }
@@ -1,31 +1,37 @@
 package agcodes
 
+import "net/http"
+
 var (
 	// No error code specified.
 	CodeNil = newLocalCoder("CodeNil", "未指定错误码", "", "")
 	// It is OK.
-	CodeOK = newLocalCoder("OK", "OK", "", "")
+	CodeOK = newLocalCoder("OK", "OK", "", "").withStatus(http.StatusOK, GRPCOK)
 	// An error occurred internally.
 	CodeInternalError = newLocalCoder("Public.InternalError",
-		"服务 [serviceName] 内部错误。", "", "")
+		"服务 [serviceName] 内部错误。", "", "").withStatus(http.StatusInternalServerError, GRPCInternal)
 
 	// Unknown error.
 	CodeUnknown = newLocalCoder("Public.UnknownError",
-		"服务 [serviceName] 内部出现未知错误。", "", "")
+		"服务 [serviceName] 内部出现未知错误。", "", "").withStatus(http.StatusInternalServerError, GRPCUnknown)
 
-	CodeInvalidParameter = newLocalCoder("Public.InvalidParameter", "调用服务 [serviceName] 接口 [interfaceName] 参数值 [params] 校验不通过。", "", "请使用请求参数构造规范化的请求字符串。详细信息参见产品 API 文档。")
+	CodeInvalidParameter = newLocalCoder("Public.InvalidParameter", "调用服务 [serviceName] 接口 [interfaceName] 参数值 [params] 校验不通过。", "", "请使用请求参数构造规范化的请求字符串。详细信息参见产品 API 文档。").withStatus(http.StatusBadRequest, GRPCInvalidArgument)
 
 	// The given parameter for current operation is invalid.
-	CodeMissingParameter = newLocalCoder("Public.MissingParameter", "调用服务 [serviceName] 接口 [interfaceName] 缺少必须参数 [params] 。", "", "请检查调用时是否填写了此参数，并重试请求。详细信息参见产品 API 文档。")
+	CodeMissingParameter = newLocalCoder("Public.MissingParameter", "调用服务 [serviceName] 接口 [interfaceName] 缺少必须参数 [params] 。", "", "请检查调用时是否填写了此参数，并重试请求。详细信息参见产品 API 文档。").withStatus(http.StatusBadRequest, GRPCInvalidArgument)
 
-	CodeUnsupportedHTTPMethod = newLocalCoder("Public.UnsupportedHTTPMethod", "服务 [serviceName] 未提供 HTTP 接口 [interfaceName] 支持。", "", "建议查看产品 API 文档。")
+	CodeUnsupportedHTTPMethod = newLocalCoder("Public.UnsupportedHTTPMethod", "服务 [serviceName] 未提供 HTTP 接口 [interfaceName] 支持。", "", "建议查看产品 API 文档。").withStatus(http.StatusMethodNotAllowed, GRPCUnimplemented)
 	//CodeUnsupportedHTTPMethod
 
-	CodeServiceUnavailable = newLocalCoder("Public.ServiceUnavailableDuringUpgrade", "服务 [serviceName] 暂时不可用。", "系统正在升级，暂时不可用。", "请稍后尝试。")
+	CodeServiceUnavailable = newLocalCoder("Public.ServiceUnavailableDuringUpgrade", "服务 [serviceName] 暂时不可用。", "系统正在升级，暂时不可用。", "请稍后尝试。").withStatus(http.StatusServiceUnavailable, GRPCUnavailable)
+
+	CodeNotFound = newLocalCoder("Public.NotFound", "Not Found", "", "").withStatus(http.StatusNotFound, GRPCNotFound) // Resource does not exist.
 
-	CodeNotFound = newLocalCoder("Public.NotFound", "Not Found", "", "") // Resource does not exist.
+	CodeNotAuthorized = newLocalCoder("Public.NotAuthorized", "Not Authorized", "", "").withStatus(http.StatusUnauthorized, GRPCUnauthenticated) // Not Authorized.
 
-	CodeNotAuthorized = newLocalCoder("Public.NotAuthorized", "Not Authorized", "", "") // Not Authorized.
+	CodeRequestTimeout = newLocalCoder("Public.RequestTimeout", "服务 [serviceName] 处理请求超时。", "", "请稍后重试，或缩小请求的数据范围。").withStatus(http.StatusGatewayTimeout, GRPCDeadlineExceeded) // Request handling exceeded its deadline.
+
+	CodeConflict = newLocalCoder("Public.Conflict", "请求与资源当前状态冲突。", "", "请检查请求后重试。").withStatus(http.StatusConflict, GRPCAborted) // Request conflicts with the current state.
 
 	//
 	//CodeValidationFailed         = localCoder{51, "Validation Failed",nil ,""}           // Data validation failed.
@@ -45,10 +51,12 @@ var (
 	//CodeInvalidRequest           = localCoder{66, "Invalid Request", nil,""}             // Invalid request.
 	//CodeBusinessValidationFailed = localCoder{300, "Business Validation Failed", nil,""} // Business validation failed.
 
-	NotAuthentication     = newLocalCoder("Public.NotAuthentication", "无用户登录信息", "", "")
-	HydraException        = newLocalCoder("Public.HydraException", "授权服务异常", "", "")
-	AuthenticationFailure = newLocalCoder("Public.AuthenticationFailure", "用户登录已过期", "", "")
-	GetUserInfoFailure    = newLocalCoder("Public.GetUserInfoFailure", "获取用户信息失败", "", "")
-	AuthorizationFailure  = newLocalCoder("Public.AuthorizationFailure", "暂无权限，您可联系系统管理员配置", "", "")
-	AccessTypeNotSupport  = newLocalCoder("Public.AccessTypeNotSupport", "暂不支持的访问类型", "", "")
+	NotAuthentication     = newLocalCoder("Public.NotAuthentication", "无用户登录信息", "", "").withStatus(http.StatusUnauthorized, GRPCUnauthenticated)
+	HydraException        = newLocalCoder("Public.HydraException", "授权服务异常", "", "").withStatus(http.StatusServiceUnavailable, GRPCUnavailable)
+	AuthenticationFailure = newLocalCoder("Public.AuthenticationFailure", "用户登录已过期", "", "").withStatus(http.StatusUnauthorized, GRPCUnauthenticated)
+	GetUserInfoFailure    = newLocalCoder("Public.GetUserInfoFailure", "获取用户信息失败", "", "").withStatus(http.StatusInternalServerError, GRPCInternal)
+	AuthorizationFailure  = newLocalCoder("Public.AuthorizationFailure", "暂无权限，您可联系系统管理员配置", "", "").withStatus(http.StatusForbidden, GRPCPermissionDenied)
+	AccessTypeNotSupport  = newLocalCoder("Public.AccessTypeNotSupport", "暂不支持的访问类型", "", "").withStatus(http.StatusBadRequest, GRPCInvalidArgument)
+
+	CodeCSRFTokenInvalid = newLocalCoder("Public.CSRFTokenInvalid", "CSRF 令牌缺失或无效。", "", "请刷新页面后重试。").withStatus(http.StatusForbidden, GRPCPermissionDenied) // Double submit cookie check failed.
 )
@@ -1,6 +1,9 @@
 package agcodes
 
-import "fmt"
+import (
+    "fmt"
+    "strings"
+)
 
 type localCoder struct {
 
@@ -20,8 +23,18 @@ type localCoder struct {
 
     // Ref specify the reference document.
     ErrorLink string `json:"error_link"`
+
+    // HTTP status and gRPC code the error is transported with, zero when unset.
+    HttpStatus int    `json:"http_status,omitempty"`
+    GrpcCode   uint32 `json:"grpc_code,omitempty"`
+
+    // args formatted into Description, kept to render it again in another locale.
+    // Stored as one string, every arg prefixed with argSep, to keep localCoder comparable.
+    args string
 }
 
+const argSep = "\x00"
+
 func newLocalCoder(code, description, cause, solution string) localCoder {
 
     return localCoder{
@@ -62,6 +75,43 @@ func (c localCoder) GetErrorLink() string {
     return c.ErrorLink
 }
 
+func (c localCoder) GetHTTPStatus() int {
+
+    return c.HttpStatus
+}
+
+func (c localCoder) GetGRPCCode() uint32 {
+
+    return c.GrpcCode
+}
+
+func (c localCoder) withStatus(httpStatus int, grpcCode uint32) localCoder {
+    c.HttpStatus = httpStatus
+    c.GrpcCode = grpcCode
+    return c
+}
+
+func (c localCoder) GetArgs() []interface{} {
+    if c.args == "" {
+        return nil
+    }
+    parts := strings.Split(c.args[len(argSep):], argSep)
+    args := make([]interface{}, len(parts))
+    for i, p := range parts {
+        args[i] = p
+    }
+    return args
+}
+
+func joinArgs(args []interface{}) string {
+    var b strings.Builder
+    for _, a := range args {
+        b.WriteString(argSep)
+        b.WriteString(fmt.Sprint(a))
+    }
+    return b.String()
+}
+
 // String returns current error code as a string.
 func (c localCoder) String() string {
     if c.Description != "" {
@@ -3,6 +3,8 @@ package agerrors
 import (
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
 )
@@ -108,7 +110,7 @@ func (err *Error) Equal(target error) bool {
 	}
 	// Code should be the same.
 	// Note that if both errors have `nil` code, they are also considered equal.
-	if err.code != Code(target) {
+	if errorCode(err.code) != errorCode(Code(target)) {
 		return false
 	}
 	// Text should be the same.
@@ -120,10 +122,15 @@ func (err *Error) Equal(target error) bool {
 
 // Is reports whether current error `err` has error `target` in its chaining errors.
 // It is just for implements for stdlib errors.Is from Go version 1.17.
+// A target carrying an error code, e.g. an errorx.ErrorCodeInfo or an error created by NewCode,
+// matches every error of the same code whatever its text, args or detail.
 func (err *Error) Is(target error) bool {
 	if Equal(err, target) {
 		return true
 	}
+	if code := sentinelCode(target); code != "" && errorCode(err.Code()) == code {
+		return true
+	}
 	nextErr := err.Unwrap()
 	if nextErr == nil {
 		return false
@@ -136,3 +143,51 @@ func (err *Error) Is(target error) bool {
 	}
 	return false
 }
+
+// Format formats the error according to the fmt.Formatter interface.
+//
+//	%v, %s   the error chain, see Error
+//	%-v, %-s the text of the current level
+//	%+v      the error chain followed by the stack of every level, see Stack
+func (err *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 's', 'v':
+		switch {
+		case s.Flag('-'):
+			if err.text != "" {
+				io.WriteString(s, err.text)
+			} else {
+				io.WriteString(s, err.Error())
+			}
+		case s.Flag('+') && verb == 'v':
+			io.WriteString(s, err.Error())
+			io.WriteString(s, "\n")
+			io.WriteString(s, err.Stack())
+		default:
+			io.WriteString(s, err.Error())
+		}
+	case 'q':
+		fmt.Fprintf(s, "%q", err.Error())
+	}
+}
+
+// Stack returns the text and the call stack of every level of the error chain.
+func (err *Error) Stack() string {
+	if err == nil {
+		return ""
+	}
+	var b strings.Builder
+	for loop, i := err, 1; loop != nil; i++ {
+		fmt.Fprintf(&b, "%d. %s\n", i, loop.Current().Error())
+		b.WriteString(loop.stack.String())
+		next, ok := loop.error.(*Error)
+		if !ok {
+			if loop.error != nil {
+				fmt.Fprintf(&b, "%d. %s\n", i+1, loop.error.Error())
+			}
+			break
+		}
+		loop = next
+	}
+	return b.String()
+}
@@ -26,10 +26,11 @@ func NewF(format string, a ...interface{}) error {
 	}
 }
 
-// Wrapf returns an error annotating err with a stack trace at the point Wrapf is called, and the format specifier.
+// Wrap returns an error annotating e with a stack trace at the point Wrap is called and the text s.
+// The code of e is kept. It returns nil if e is nil.
 func Wrap(e error, s string) error {
 
-	if e != nil {
+	if e == nil {
 		return nil
 	}
 
@@ -40,3 +41,34 @@ func Wrap(e error, s string) error {
 		code:  Code(e),
 	}
 }
+
+// Wrapf is Wrap with a formatted text.
+func Wrapf(e error, format string, a ...interface{}) error {
+
+	if e == nil {
+		return nil
+	}
+
+	return &Error{
+		error: e,
+		stack: callers(),
+		text:  fmt.Sprintf(format, a...),
+		code:  Code(e),
+	}
+}
+
+// NewPanic converts a value recovered from a panic into an error with code CodeInternalError
+// and the stack of the panicking goroutine. It must be called in the deferred function that recovered.
+func NewPanic(recovered interface{}) error {
+	e := &Error{
+		stack: callers(),
+		code:  agcodes.CodeInternalError,
+	}
+	if err, ok := recovered.(error); ok {
+		e.error = err
+		e.text = "panic"
+	} else {
+		e.text = fmt.Sprintf("panic: %v", recovered)
+	}
+	return e
+}
@@ -27,6 +27,51 @@ func NewCodeF(code agcodes.Coder, format string, a ...interface{}) error {
 	}
 }
 
+// WrapCode returns an error with code wrapping e, the text defaults to the code description.
+// It returns nil if e is nil.
+func WrapCode(code agcodes.Coder, e error, s ...string) error {
+	if e == nil {
+		return nil
+	}
+	return &Error{
+		error: e,
+		stack: callers(),
+		text:  strings.Join(s, ", "),
+		code:  code,
+	}
+}
+
+// HTTPStatus returns the HTTP status of the code of err, see agcodes.HTTPStatus.
+func HTTPStatus(err error) int {
+	return agcodes.HTTPStatus(Code(err))
+}
+
+// GRPCCode returns the gRPC code of the code of err, see agcodes.GRPCCode.
+func GRPCCode(err error) uint32 {
+	return agcodes.GRPCCode(Code(err))
+}
+
+// sentinelCode returns the error code carried by target, "" when it has none.
+func sentinelCode(target error) string {
+	e, ok := target.(ICode)
+	if !ok {
+		return ""
+	}
+	code := errorCode(e.Code())
+	if code == agcodes.CodeNil.GetErrorCode() {
+		return ""
+	}
+	return code
+}
+
+func errorCode(code agcodes.Coder) string {
+	if code == nil {
+		return ""
+	}
+	return code.GetErrorCode()
+}
+
+// Code returns the error code of err, CodeNil for nil and a Public.InternalError code for errors without one.
 func Code(err error) agcodes.Coder {
 	if e, ok := err.(ICode); ok {
 		return e.Code()
@@ -41,12 +86,13 @@ func Code(err error) agcodes.Coder {
 }
 
 // HasCode checks and reports whether `err` has `code` in its chaining errors.
+// Codes are compared by error code, their args and detail are ignored.
 func HasCode(err error, code agcodes.Coder) bool {
 	if err == nil {
 		return false
 	}
 	if e, ok := err.(ICode); ok {
-		return code == e.Code()
+		return errorCode(code) == errorCode(e.Code())
 	}
 	if e, ok := err.(IUnwrap); ok {
 		return HasCode(e.Unwrap(), code)
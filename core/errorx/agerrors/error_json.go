@@ -0,0 +1,102 @@
+package agerrors
+
+import (
+	"encoding/json"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+)
+
+// jsonError is the json body of an error, the shape of ginx.HttpError.
+type jsonError struct {
+	Code        string          `json:"code"`
+	Description string          `json:"description"`
+	Solution    string          `json:"solution,omitempty"`
+	Cause       string          `json:"cause,omitempty"`
+	Detail      json.RawMessage `json:"detail,omitempty"`
+	Link        string          `json:"link,omitempty"`
+}
+
+// RawDetail is a json encoded error detail. It is kept as a string so that codes holding it stay comparable,
+// use DecodeDetail to read it.
+type RawDetail string
+
+// MarshalJSON returns the detail as is.
+func (d RawDetail) MarshalJSON() ([]byte, error) {
+	if d == "" {
+		return []byte("null"), nil
+	}
+	return []byte(d), nil
+}
+
+// MarshalJSON encodes the code of err as a HttpError body. Errors without a code are
+// encoded as Public.InternalError described by their text.
+func (err *Error) MarshalJSON() ([]byte, error) {
+	code := err.Code()
+	body := jsonError{
+		Code:        code.GetErrorCode(),
+		Description: code.GetDescription(),
+		Solution:    code.GetSolution(),
+		Cause:       code.GetCause(),
+		Link:        code.GetErrorLink(),
+	}
+	if body.Code == agcodes.CodeNil.GetErrorCode() {
+		body.Code = agcodes.CodeInternalError.GetErrorCode()
+		body.Description = err.Error()
+	}
+	if detail := code.GetErrorDetails(); detail != nil {
+		raw, e := json.Marshal(detail)
+		if e != nil {
+			return nil, e
+		}
+		body.Detail = raw
+	}
+	return json.Marshal(body)
+}
+
+// UnmarshalJSON decodes a HttpError body into err, the detail is kept as a RawDetail.
+func (err *Error) UnmarshalJSON(data []byte) error {
+	var body jsonError
+	if e := json.Unmarshal(data, &body); e != nil {
+		return e
+	}
+	err.code = body.coder()
+	err.text = ""
+	err.error = nil
+	return nil
+}
+
+func (body *jsonError) coder() agcodes.Coder {
+	var detail interface{}
+	if len(body.Detail) > 0 && string(body.Detail) != "null" {
+		detail = RawDetail(body.Detail)
+	}
+	return agcodes.New(body.Code, body.Description, body.Cause, body.Solution, detail, body.Link)
+}
+
+// FromResponse rehydrates the error of a response with status and HttpError body, so that
+// errors.Is matches the sentinel of its code and HTTPStatus returns status.
+// It returns nil if body is not a HttpError.
+func FromResponse(status int, body []byte) error {
+	var e jsonError
+	if json.Unmarshal(body, &e) != nil || e.Code == "" {
+		return nil
+	}
+	return &Error{
+		stack: callers(),
+		code:  agcodes.WithStatus(e.coder(), status, 0),
+	}
+}
+
+// DecodeDetail decodes the detail of the code of err into v, e.g. the detail of an error
+// received from another service.
+func DecodeDetail(err error, v interface{}) error {
+	detail := Code(err).GetErrorDetails()
+	if raw, ok := detail.(RawDetail); ok {
+		return json.Unmarshal([]byte(raw), v)
+	}
+	data, e := json.Marshal(detail)
+	if e != nil {
+		return e
+	}
+	return json.Unmarshal(data, v)
+}
@@ -120,3 +120,16 @@ func callers(skip ...int) stack {
     }
     return pcs[:runtime.Callers(n, pcs[:])]
 }
+
+// String formats the stack one frame per line pair as "<funcname>\n\t<file>:<line>".
+func (st stack) String() string {
+    var b strings.Builder
+    for _, pc := range st {
+        f := Frame(pc)
+        if f.name() == "unknown" {
+            continue
+        }
+        fmt.Fprintf(&b, "%+v\n", f)
+    }
+    return b.String()
+}
@@ -1,6 +1,11 @@
 package agerrors
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
@@ -33,3 +38,115 @@ func Test_Code(t *testing.T) {
 	})
 
 }
+
+func Test_NewPanic(t *testing.T) {
+
+	t.Run("value", func(t *testing.T) {
+		var err error
+		func() {
+			defer func() { err = NewPanic(recover()) }()
+			panic("boom")
+		}()
+		assert.Equal(t, "panic: boom", err.Error())
+		assert.True(t, HasCode(err, agcodes.CodeInternalError))
+		assert.True(t, HasStack(err))
+		assert.Contains(t, Stack(err), "Test_NewPanic")
+	})
+
+	t.Run("error", func(t *testing.T) {
+		cause := New("cause")
+		err := NewPanic(cause)
+		assert.Equal(t, "panic: cause", err.Error())
+		assert.Contains(t, Stack(err), "2. cause")
+	})
+
+}
+
+func Test_Wrap(t *testing.T) {
+
+	t.Run("nil", func(t *testing.T) {
+		assert.Nil(t, Wrap(nil, "text"))
+		assert.Nil(t, Wrapf(nil, "%d", 1))
+		assert.Nil(t, WrapCode(agcodes.CodeNotFound, nil))
+	})
+
+	t.Run("keeps code", func(t *testing.T) {
+		err := Wrapf(NewCode(agcodes.CodeNotFound, "user 1"), "load %s", "user")
+		assert.Equal(t, "load user: user 1", err.Error())
+		assert.True(t, HasCode(err, agcodes.CodeNotFound))
+	})
+
+	t.Run("wrap code", func(t *testing.T) {
+		cause := errors.New("connection refused")
+		err := WrapCode(agcodes.CodeServiceUnavailable, cause)
+		assert.True(t, errors.Is(err, cause))
+		assert.Equal(t, http.StatusServiceUnavailable, HTTPStatus(err))
+		assert.Equal(t, agcodes.GRPCUnavailable, GRPCCode(err))
+	})
+
+}
+
+func Test_Format(t *testing.T) {
+	err := Wrap(NewCode(agcodes.CodeNotFound, "user 1"), "load user")
+	assert.Equal(t, "load user: user 1", fmt.Sprintf("%v", err))
+	assert.Equal(t, "load user", fmt.Sprintf("%-v", err))
+	assert.Equal(t, `"load user: user 1"`, fmt.Sprintf("%q", err))
+
+	detailed := fmt.Sprintf("%+v", err)
+	assert.True(t, strings.HasPrefix(detailed, "load user: user 1\n1. load user\n"))
+	assert.Contains(t, detailed, "2. user 1")
+	assert.Contains(t, detailed, "Test_Format")
+}
+
+func Test_Is(t *testing.T) {
+	sentinel := NewCode(agcodes.CodeNotFound)
+
+	err := fmt.Errorf("handler: %w", Wrap(NewCode(agcodes.WithCode(agcodes.CodeNotFound, "user 1"), "no user"), "load"))
+	assert.True(t, errors.Is(err, sentinel))
+	assert.False(t, errors.Is(err, NewCode(agcodes.CodeConflict)))
+	assert.False(t, errors.Is(err, New("no user")))
+
+	var target *Error
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, agcodes.CodeNotFound.GetErrorCode(), target.Code().GetErrorCode())
+}
+
+func Test_JSON(t *testing.T) {
+
+	t.Run("round trip", func(t *testing.T) {
+		code := agcodes.WithCode(agcodes.CodeInvalidParameter, map[string]string{"field": "name"})
+		data, err := json.Marshal(NewCode(code))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"code":"Public.InvalidParameter","description":"`+code.GetDescription()+`","solution":"`+code.GetSolution()+`","detail":{"field":"name"}}`, string(data))
+
+		decoded := &Error{}
+		assert.NoError(t, json.Unmarshal(data, decoded))
+		assert.True(t, errors.Is(decoded, NewCode(agcodes.CodeInvalidParameter)))
+
+		var detail map[string]string
+		assert.NoError(t, DecodeDetail(decoded, &detail))
+		assert.Equal(t, "name", detail["field"])
+
+		again, err := json.Marshal(decoded)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(data), string(again))
+	})
+
+	t.Run("without code", func(t *testing.T) {
+		data, err := json.Marshal(New("boom"))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"code":"Public.InternalError","description":"boom"}`, string(data))
+	})
+
+	t.Run("from response", func(t *testing.T) {
+		body := []byte(`{"code":"Public.NotFound","description":"Not Found","detail":{"id":"1"}}`)
+		err := FromResponse(http.StatusNotFound, body)
+		assert.True(t, errors.Is(err, NewCode(agcodes.CodeNotFound)))
+		assert.Equal(t, http.StatusNotFound, HTTPStatus(err))
+		assert.Equal(t, "Not Found", err.Error())
+
+		assert.Nil(t, FromResponse(http.StatusBadGateway, []byte("bad gateway")))
+		assert.Nil(t, FromResponse(http.StatusBadRequest, []byte(`{"code":400}`)))
+	})
+
+}
@@ -9,30 +9,71 @@ import (
 	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
 )
 
+// ErrorCodeInfo a registered error code. It is an error itself, so that it can be used as the
+// sentinel of its code: errors.Is(err, ErrNotFound) reports whether err has the code of ErrNotFound.
 type ErrorCodeInfo struct {
 	code        string
 	description string
 	cause       string
 	solution    string
+	link        string
+	httpStatus  int
+	grpcCode    uint32
 }
 
 func (e *ErrorCodeInfo) GetCode() string {
 	return e.code
 }
 
+// WithHTTPStatus sets the HTTP status the code is answered with.
+func (e *ErrorCodeInfo) WithHTTPStatus(status int) *ErrorCodeInfo {
+	e.httpStatus = status
+	return e
+}
+
+// WithGRPCCode sets the gRPC code the code is answered with, see agcodes.GRPCCode.
+func (e *ErrorCodeInfo) WithGRPCCode(code uint32) *ErrorCodeInfo {
+	e.grpcCode = code
+	return e
+}
+
+// WithLink sets the link to the document of the code.
+func (e *ErrorCodeInfo) WithLink(link string) *ErrorCodeInfo {
+	e.link = link
+	return e
+}
+
+// Error returns the code and its description.
+func (e *ErrorCodeInfo) Error() string {
+	return e.code + ": " + e.description
+}
+
+// Code returns the code without detail, it makes ErrorCodeInfo a sentinel for errors.Is.
+func (e *ErrorCodeInfo) Code() agcodes.Coder {
+	return e.coder(e.description, nil)
+}
+
+func (e *ErrorCodeInfo) coder(desc string, detail any) agcodes.Coder {
+	coder := agcodes.New(e.code, desc, e.cause, e.solution, detail, e.link)
+	if e.httpStatus != 0 || e.grpcCode != 0 {
+		coder = agcodes.WithStatus(coder, e.httpStatus, e.grpcCode)
+	}
+	return coder
+}
+
 func (e *ErrorCodeInfo) Err() error {
-	return e.newCoder(e.code, nil)
+	return e.newCoder(nil)
 }
 
 func (e *ErrorCodeInfo) Desc(args ...any) error {
-	return e.newCoder(e.code, nil, args...)
+	return e.newCoder(nil, args...)
 }
 
 func (e *ErrorCodeInfo) Detail(err any, args ...any) error {
-	return e.newCoder(e.code, err, args...)
+	return e.newCoder(err, args...)
 }
 
-func (e *ErrorCodeInfo) newCoder(errCode string, err any, args ...any) error {
+func (e *ErrorCodeInfo) newCoder(err any, args ...any) error {
 	desc := e.description
 	if len(args) > 0 {
 		desc = FormatDescription(desc, args...)
@@ -41,7 +82,10 @@ func (e *ErrorCodeInfo) newCoder(errCode string, err any, args ...any) error {
 		err = struct{}{}
 	}
 
-	coder := agcodes.New(errCode, desc, e.cause, e.solution, err, "")
+	coder := e.coder(desc, err)
+	if len(args) > 0 {
+		coder = agcodes.WithArgs(coder, args...)
+	}
 	return agerrors.NewCode(coder)
 }
 
@@ -51,17 +95,9 @@ func IsCode(err error) bool {
 	return ok
 }
 
-// Is 比较两个err是不是同一个err
+// Is 比较两个err是不是同一个err, 错误码相同即为同一个err, 等同于 errors.Is(erra, errb)
 func Is(erra, errb error) bool {
-	a, aok := erra.(*agerrors.Error)
-	b, bok := errb.(*agerrors.Error)
-	if !aok && !bok {
-		return errors.Is(erra, errb)
-	}
-	if aok && bok {
-		return a.Code() == b.Code()
-	}
-	return false
+	return errors.Is(erra, errb)
 }
 
 // FormatDescription replace the placeholder in coder.Description
@@ -0,0 +1,36 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testConflict = testModule.Description("Conflict", "逻辑视图 [name] 已存在。").
+	WithHTTPStatus(http.StatusConflict).
+	WithGRPCCode(agcodes.GRPCAlreadyExists).
+	WithLink("https://example.com/errors/conflict")
+
+func TestErrorCodeInfoSentinel(t *testing.T) {
+	err := fmt.Errorf("create view: %w", testConflict.Detail(map[string]string{"name": "v1"}, "v1"))
+
+	assert.True(t, errors.Is(err, testConflict))
+	assert.False(t, errors.Is(err, testNotFound))
+	assert.True(t, Is(testConflict.Desc("v2"), testConflict))
+	assert.True(t, Is(testConflict.Err(), testConflict.Desc("v2")))
+
+	code := agerrors.Code(err)
+	assert.Equal(t, "逻辑视图 [v1] 已存在。", code.GetDescription())
+	assert.Equal(t, "https://example.com/errors/conflict", code.GetErrorLink())
+	assert.Equal(t, http.StatusConflict, agerrors.HTTPStatus(err))
+	assert.Equal(t, agcodes.GRPCAlreadyExists, agerrors.GRPCCode(err))
+
+	// the status survives localization
+	assert.Equal(t, http.StatusConflict, agcodes.HTTPStatus(LocalizeIn("en", code)))
+}
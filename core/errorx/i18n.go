@@ -0,0 +1,159 @@
+package errorx
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+	"github.com/kweaver-ai/idrm-go-frame/core/i18n"
+)
+
+// catalog keys of an error code: "<code>.description", "<code>.cause", "<code>.solution"
+const (
+	keyDescription = ".description"
+	keyCause       = ".cause"
+	keySolution    = ".solution"
+)
+
+//go:embed catalog
+var builtinFS embed.FS
+
+// builtin holds the translations of the agcodes public codes, the catalogs of i18n.Default() win.
+var builtin = func() *i18n.Bundle {
+	b := i18n.NewBundle(i18n.DefaultLocale)
+	if err := b.LoadFS(builtinFS, "catalog"); err != nil {
+		panic(fmt.Sprintf("errorx: load builtin catalogs: %v", err))
+	}
+	return b
+}()
+
+// LoadCatalogs loads the error catalogs under dir of fsys into i18n.Default(), usually an embed.FS
+// next to the module declaring the codes. Catalogs are keyed by the full error code:
+//
+//	//go:embed i18n
+//	var catalogs embed.FS
+//
+//	var _ = errorx.LoadCatalogs(catalogs, "i18n")
+//
+//	# i18n/errors.en.yaml
+//	DataView.FormView.NotFound:
+//	  description: "Logic view [id] not found."
+//	  solution: "Check the id and retry."
+func LoadCatalogs(fsys fs.FS, dir string) error {
+	return i18n.Default().LoadFS(fsys, dir)
+}
+
+// Localize renders code in the locale of ctx, see i18n.FromContext.
+func Localize(ctx context.Context, code agcodes.Coder) agcodes.Coder {
+	return LocalizeIn(i18n.FromContext(ctx), code)
+}
+
+// LocalizeIn renders code in locale with the messages keyed by the full error code,
+// parts without a message keep the registered text. The description is formatted again with the
+// args it was created with, see ErrorCodeInfo.Desc.
+func LocalizeIn(locale string, code agcodes.Coder) agcodes.Coder {
+	if code == nil {
+		return code
+	}
+	errCode := code.GetErrorCode()
+	desc, okDesc := lookup(locale, errCode+keyDescription)
+	cause, okCause := lookup(locale, errCode+keyCause)
+	solution, okSolution := lookup(locale, errCode+keySolution)
+	if !okDesc && !okCause && !okSolution {
+		return code
+	}
+
+	args := agcodes.Args(code)
+	if okDesc {
+		desc = FormatDescription(desc, args...)
+	} else {
+		desc = code.GetDescription()
+	}
+	if !okCause {
+		cause = code.GetCause()
+	}
+	if !okSolution {
+		solution = code.GetSolution()
+	}
+	localized := agcodes.New(errCode, desc, cause, solution, code.GetErrorDetails(), code.GetErrorLink())
+	if len(args) > 0 {
+		localized = agcodes.WithArgs(localized, args...)
+	}
+	if sc, ok := code.(agcodes.StatusCoder); ok {
+		localized = agcodes.WithStatus(localized, sc.GetHTTPStatus(), sc.GetGRPCCode())
+	}
+	return localized
+}
+
+// LocalizeError returns err with its code rendered in the locale of ctx, for responses not written by ginx.
+func LocalizeError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	code := agerrors.Code(err)
+	localized := Localize(ctx, code)
+	if localized == code {
+		return err
+	}
+	return agerrors.NewCode(localized)
+}
+
+// lookup searches the locale chain in i18n.Default() and then in the builtin catalogs,
+// the default locale of i18n.Default() last.
+func lookup(locale, key string) (string, bool) {
+	bundle := i18n.Default()
+	chain := bundle.Fallbacks(locale)
+	for _, l := range chain[:len(chain)-1] {
+		if msg, ok := bundle.Message(l, key); ok {
+			return msg, true
+		}
+		if msg, ok := builtin.Message(l, key); ok {
+			return msg, true
+		}
+	}
+	return bundle.Message(bundle.DefaultLocale(), key)
+}
+
+// CheckCatalogs reports the registered codes missing a translation in one of locales,
+// every supported locale of i18n.Default() but the default one when locales is empty.
+// The cause and the solution are only required when the registered code has them.
+// Call it in a test of the service so that a new code can not ship untranslated:
+//
+//	func TestErrorCatalogs(t *testing.T) {
+//		require.NoError(t, errorx.LoadCatalogs(catalogs, "i18n"))
+//		assert.NoError(t, errorx.CheckCatalogs())
+//	}
+func CheckCatalogs(locales ...string) error {
+	bundle := i18n.Default()
+	if len(locales) == 0 {
+		locales = bundle.Locales()[1:]
+	}
+
+	var missing []string
+	for _, locale := range locales {
+		for code, info := range errorCodeMap {
+			keys := []string{code + keyDescription}
+			if info.cause != "" {
+				keys = append(keys, code+keyCause)
+			}
+			if info.solution != "" {
+				keys = append(keys, code+keySolution)
+			}
+			for _, key := range keys {
+				if !bundle.Has(locale, key) {
+					missing = append(missing, locale+": "+key)
+				}
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("errorx: %d untranslated messages:\n%s", len(missing), strings.Join(missing, "\n"))
+}
@@ -0,0 +1,70 @@
+package errorx
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+	"github.com/kweaver-ai/idrm-go-frame/core/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata
+var testCatalogs embed.FS
+
+var (
+	testModule   = New("Test.Catalog.")
+	testNotFound = testModule.Solution("NotFound", "逻辑视图 [id] 不存在。", "", "请检查后重试。")
+)
+
+func TestLocalize(t *testing.T) {
+	old := i18n.Default()
+	i18n.SetDefault(i18n.NewBundle("zh", "en"))
+	defer i18n.SetDefault(old)
+	require.NoError(t, LoadCatalogs(testCatalogs, "testdata"))
+
+	en := i18n.NewContext(context.Background(), "en")
+	code := agerrors.Code(testNotFound.Desc("42"))
+	assert.Equal(t, "Logic view [42] not found.", Localize(en, code).GetDescription())
+	assert.Equal(t, "Check the id and retry.", Localize(en, code).GetSolution())
+	assert.Equal(t, "逻辑视图 [42] 不存在。", Localize(context.Background(), code).GetDescription())
+
+	// builtin catalogs of the public codes
+	assert.Equal(t, "The login has expired.", Localize(en, agcodes.AuthenticationFailure).GetDescription())
+
+	err := LocalizeError(en, testNotFound.Detail("detail", "7"))
+	assert.Equal(t, "Logic view [7] not found.", agerrors.Code(err).GetDescription())
+	assert.Equal(t, "detail", agerrors.Code(err).GetErrorDetails())
+
+	assert.NoError(t, CheckCatalogs())
+	assert.ErrorContains(t, CheckCatalogs("fr"), "fr: Test.Catalog.NotFound.description")
+}
+
+func TestHandler(t *testing.T) {
+	old := i18n.Default()
+	i18n.SetDefault(i18n.NewBundle("zh", "en"))
+	defer i18n.SetDefault(old)
+	require.NoError(t, LoadCatalogs(testCatalogs, "testdata"))
+
+	req := httptest.NewRequest(http.MethodGet, "/error-codes", nil)
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, req)
+
+	var body struct {
+		Entries []CodeEntry `json:"entries"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Contains(t, body.Entries, CodeEntry{
+		Code:        "Test.Catalog.NotFound",
+		Description: "Logic view [id] not found.",
+		Solution:    "Check the id and retry.",
+	})
+}
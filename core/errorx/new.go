@@ -6,11 +6,11 @@ const (
 	publicPreCoder = "Basic.Public."
 )
 
-var errorCodeMap = make(map[string]ErrorCodeInfo)
+var errorCodeMap = make(map[string]*ErrorCodeInfo)
 
 type Module struct {
 	preCode      string
-	errorCodeMap map[string]ErrorCodeInfo
+	errorCodeMap map[string]*ErrorCodeInfo
 }
 
 func New(preCode string) *Module {
@@ -32,7 +32,7 @@ func (m *Module) Solution(code, desc, cause, solution string) *ErrorCodeInfo {
 	if _, ok := m.errorCodeMap[e.code]; ok {
 		panic(fmt.Sprintf("error code is not allowed to repeat, code: %s", e.code))
 	}
-	m.errorCodeMap[e.code] = *e
+	m.errorCodeMap[e.code] = e
 	return e
 }
 
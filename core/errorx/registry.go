@@ -0,0 +1,56 @@
+package errorx
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/i18n"
+)
+
+// CodeEntry a registered error code, see Codes.
+type CodeEntry struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+	Cause       string `json:"cause,omitempty"`
+	Solution    string `json:"solution,omitempty"`
+	Link        string `json:"link,omitempty"`
+	HTTPStatus  int    `json:"http_status,omitempty"`
+}
+
+// Codes returns the codes registered with Module, sorted by code.
+func Codes() []CodeEntry {
+	entries := make([]CodeEntry, 0, len(errorCodeMap))
+	for _, info := range errorCodeMap {
+		entries = append(entries, CodeEntry{
+			Code:        info.code,
+			Description: info.description,
+			Cause:       info.cause,
+			Solution:    info.solution,
+			Link:        info.link,
+			HTTPStatus:  info.httpStatus,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Code < entries[j].Code })
+	return entries
+}
+
+// Handler lists the registered codes as json, rendered in the locale matching Accept-Language:
+//
+//	engine.GET("/api/internal/data-view/v1/error-codes", gin.WrapH(errorx.Handler()))
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := i18n.Default().Match(r.Header.Get("Accept-Language"))
+		entries := Codes()
+		for i, e := range entries {
+			code := LocalizeIn(locale, agcodes.New(e.Code, e.Description, e.Cause, e.Solution, nil, ""))
+			entries[i].Description = code.GetDescription()
+			entries[i].Cause = code.GetCause()
+			entries[i].Solution = code.GetSolution()
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("Content-Language", locale)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"entries": entries, "total_count": len(entries)})
+	})
+}
@@ -0,0 +1,127 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/encoding"
+	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/json"
+	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/yaml"
+)
+
+// LoadPath loads a catalog file or every catalog file of a directory.
+func (b *Bundle) LoadPath(p string) error {
+	info, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return b.LoadFS(os.DirFS(p), ".")
+	}
+	return b.LoadFile(p)
+}
+
+// LoadFile loads one catalog file. The locale is the last dot separated part of the
+// file name before the extension: "en.yaml" and "errors.en.yaml" both hold "en" messages.
+// Nested keys are joined with dots, so these two catalogs are the same:
+//
+//	validator.required: "{0} is required"
+//
+//	validator:
+//	  required: "{0} is required"
+func (b *Bundle) LoadFile(p string) error {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return err
+	}
+	return b.load(filepath.Base(p), data)
+}
+
+// LoadFS loads every yaml/json catalog file directly under dir of fsys, e.g. an embed.FS:
+//
+//	//go:embed i18n
+//	var catalogs embed.FS
+//
+//	err := i18n.Default().LoadFS(catalogs, "i18n")
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || codecOf(entry.Name()) == nil {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := b.load(entry.Name(), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *Bundle) load(name string, data []byte) error {
+	codec := codecOf(name)
+	if codec == nil {
+		return fmt.Errorf("i18n: unsupported catalog format %q", name)
+	}
+	locale := localeOf(name)
+	if locale == "" {
+		return fmt.Errorf("i18n: no locale in catalog name %q", name)
+	}
+	var raw map[string]interface{}
+	if err := codec.Unmarshal(data, &raw); err != nil {
+		return fmt.Errorf("i18n: parse catalog %s: %w", name, err)
+	}
+	messages := make(map[string]string)
+	flatten("", raw, messages)
+	b.AddMessages(locale, messages)
+	return nil
+}
+
+func codecOf(name string) encoding.Codec {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+		return encoding.GetCodec("yaml")
+	case ".json":
+		return encoding.GetCodec("json")
+	}
+	return nil
+}
+
+func localeOf(name string) string {
+	name = strings.TrimSuffix(name, path.Ext(name))
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return Normalize(name)
+}
+
+func flatten(prefix string, v interface{}, out map[string]string) {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, child := range vv {
+			flatten(join(k), child, out)
+		}
+	case map[interface{}]interface{}:
+		for k, child := range vv {
+			flatten(join(fmt.Sprint(k)), child, out)
+		}
+	case nil:
+	default:
+		out[prefix] = fmt.Sprint(vv)
+	}
+}
@@ -0,0 +1,59 @@
+package i18n
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+type localeCtxKey struct{}
+
+// NewContext returns a copy of ctx carrying locale.
+func NewContext(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeCtxKey{}, locale)
+}
+
+// FromContext returns the locale selected by the middleware. For a gin context without it
+// the Accept-Language header is matched, otherwise the default locale is returned.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return Default().DefaultLocale()
+	}
+	if c, ok := ctx.(*gin.Context); ok {
+		if locale := c.GetString(LocaleKey); locale != "" {
+			return locale
+		}
+		if c.Request == nil {
+			return Default().DefaultLocale()
+		}
+		if locale, ok := c.Request.Context().Value(localeCtxKey{}).(string); ok && locale != "" {
+			return locale
+		}
+		return Default().Match(c.GetHeader("Accept-Language"))
+	}
+	if locale, ok := ctx.Value(localeCtxKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return Default().DefaultLocale()
+}
+
+// T translates key in the locale of ctx with the default bundle.
+func T(ctx context.Context, key string, args ...interface{}) string {
+	return Default().T(FromContext(ctx), key, args...)
+}
+
+// Middleware selects the locale of the request from Accept-Language and stores it in
+// the gin and the request context. b nil uses the default bundle at request time.
+func Middleware(b *Bundle) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		bundle := b
+		if bundle == nil {
+			bundle = Default()
+		}
+		locale := bundle.Match(c.GetHeader("Accept-Language"))
+		c.Set(LocaleKey, locale)
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), locale))
+		c.Header("Content-Language", locale)
+		c.Next()
+	}
+}
@@ -0,0 +1,217 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	// DefaultLocale is the locale of the messages registered in code.
+	DefaultLocale = "zh"
+	// LocaleKey gin context key of the request locale
+	LocaleKey = "i18n.locale"
+)
+
+// Config i18n 配置
+type Config struct {
+	// Default locale used when nothing else matches, default DefaultLocale
+	Default string `json:"default"`
+	// Locales supported locales, the default locale is always supported
+	Locales []string `json:"locales"`
+	// Catalogs message catalog files or directories, see Bundle.LoadFile
+	Catalogs []string `json:"catalogs"`
+}
+
+// Bundle holds the message catalogs of every supported locale.
+// Lookups fall back from "zh-Hant-TW" to "zh-Hant", "zh" and finally to the default locale.
+type Bundle struct {
+	defaultLocale string
+	locales       []string
+	matcher       language.Matcher
+
+	mu       sync.RWMutex
+	messages map[string]map[string]string
+}
+
+// NewBundle create a bundle supporting defaultLocale and locales.
+func NewBundle(defaultLocale string, locales ...string) *Bundle {
+	if defaultLocale == "" {
+		defaultLocale = DefaultLocale
+	}
+	defaultLocale = Normalize(defaultLocale)
+	supported := []string{defaultLocale}
+	seen := map[string]bool{defaultLocale: true}
+	for _, l := range locales {
+		l = Normalize(l)
+		if l == "" || seen[l] {
+			continue
+		}
+		seen[l] = true
+		supported = append(supported, l)
+	}
+	tags := make([]language.Tag, 0, len(supported))
+	for _, l := range supported {
+		tags = append(tags, language.Make(l))
+	}
+	return &Bundle{
+		defaultLocale: defaultLocale,
+		locales:       supported,
+		matcher:       language.NewMatcher(tags),
+		messages:      make(map[string]map[string]string),
+	}
+}
+
+// DefaultLocale returns the fallback locale of the bundle.
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale
+}
+
+// Locales returns the supported locales, the default locale first.
+func (b *Bundle) Locales() []string {
+	return append([]string(nil), b.locales...)
+}
+
+// AddMessages adds or overrides messages of locale.
+func (b *Bundle) AddMessages(locale string, messages map[string]string) {
+	locale = Normalize(locale)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	m, ok := b.messages[locale]
+	if !ok {
+		m = make(map[string]string, len(messages))
+		b.messages[locale] = m
+	}
+	for k, v := range messages {
+		m[k] = v
+	}
+}
+
+// Has reports whether locale itself, without fallback, has a message for key.
+func (b *Bundle) Has(locale, key string) bool {
+	_, ok := b.Message(locale, key)
+	return ok
+}
+
+// Message returns the message of key in locale itself, without fallback.
+func (b *Bundle) Message(locale, key string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	msg, ok := b.messages[Normalize(locale)][key]
+	return msg, ok
+}
+
+// Keys returns the sorted message keys of locale with prefix.
+func (b *Bundle) Keys(locale, prefix string) []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var keys []string
+	for k := range b.messages[Normalize(locale)] {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Lookup returns the message of key in locale, falling back to the parent locales and the default locale.
+func (b *Bundle) Lookup(locale, key string) (string, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, l := range b.Fallbacks(locale) {
+		if msg, ok := b.messages[l][key]; ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// T returns the message of key in locale formatted with args, the key itself when it is missing.
+func (b *Bundle) T(locale, key string, args ...interface{}) string {
+	msg, ok := b.Lookup(locale, key)
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Match returns the supported locale best matching an Accept-Language header value,
+// the default locale when nothing matches.
+func (b *Bundle) Match(acceptLanguage string) string {
+	if acceptLanguage == "" {
+		return b.defaultLocale
+	}
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return b.defaultLocale
+	}
+	_, idx, conf := b.matcher.Match(tags...)
+	if conf == language.No {
+		return b.defaultLocale
+	}
+	return b.locales[idx]
+}
+
+// Fallbacks returns the lookup chain of locale, e.g. "zh-Hant-TW", "zh-Hant", "zh", then the default locale.
+func (b *Bundle) Fallbacks(locale string) []string {
+	locale = Normalize(locale)
+	var chain []string
+	for locale != "" {
+		chain = append(chain, locale)
+		idx := strings.LastIndex(locale, "-")
+		if idx < 0 {
+			break
+		}
+		locale = locale[:idx]
+	}
+	return append(chain, b.defaultLocale)
+}
+
+// Normalize returns the canonical BCP 47 form of a locale, "zh_Hant_TW" becomes "zh-Hant-TW".
+func Normalize(locale string) string {
+	locale = strings.TrimSpace(strings.ReplaceAll(locale, "_", "-"))
+	if locale == "" {
+		return ""
+	}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return locale
+	}
+	return tag.String()
+}
+
+var std atomic.Pointer[Bundle]
+
+func init() {
+	std.Store(NewBundle(DefaultLocale, "en"))
+}
+
+// Default returns the process wide bundle, supporting zh and en until Init or SetDefault.
+func Default() *Bundle {
+	return std.Load()
+}
+
+// SetDefault replaces the process wide bundle.
+func SetDefault(b *Bundle) {
+	std.Store(b)
+}
+
+// Init builds the bundle of conf, loads its catalogs and installs it as the default.
+func Init(conf Config) (*Bundle, error) {
+	b := NewBundle(conf.Default, conf.Locales...)
+	for _, path := range conf.Catalogs {
+		if err := b.LoadPath(path); err != nil {
+			return nil, err
+		}
+	}
+	SetDefault(b)
+	return b, nil
+}
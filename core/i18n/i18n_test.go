@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleMatch(t *testing.T) {
+	b := NewBundle("zh", "en", "zh_Hant_TW")
+	assert.Equal(t, []string{"zh", "en", "zh-Hant-TW"}, b.Locales())
+
+	cases := map[string]string{
+		"":                        "zh",
+		"en-US,en;q=0.9":          "en",
+		"fr-FR, en;q=0.5":         "en",
+		"fr":                      "zh",
+		"zh-TW":                   "zh-Hant-TW",
+		"de;q=0.9, zh-CN;q=0.8":   "zh",
+		"en;q=0.1, zh-Hans;q=0.9": "zh",
+		"invalid;;q=abc,,":        "zh",
+	}
+	for header, want := range cases {
+		assert.Equal(t, want, b.Match(header), header)
+	}
+}
+
+func TestBundleLookup(t *testing.T) {
+	b := NewBundle("zh", "en", "zh-TW")
+	require.NoError(t, b.LoadFS(os.DirFS("testdata"), "."))
+	b.AddMessages("zh", map[string]string{"validator.required": "{0}为必填字段"})
+
+	msg, ok := b.Lookup("en", "validator.required")
+	assert.True(t, ok)
+	assert.Equal(t, "{0} is required", msg)
+
+	// en-GB falls back to en, fr to the default locale
+	msg, _ = b.Lookup("en-GB", "enum.CommonStatus.ready")
+	assert.Equal(t, "Not started", msg)
+	msg, _ = b.Lookup("fr", "validator.required")
+	assert.Equal(t, "{0}为必填字段", msg)
+
+	assert.True(t, b.Has("zh-TW", "Public.InternalError.description"))
+	assert.False(t, b.Has("zh-TW", "validator.required"))
+	assert.Equal(t, []string{"validator.required"}, b.Keys("en", "validator."))
+	assert.Equal(t, "missing.key", b.T("en", "missing.key"))
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	b := NewBundle("zh", "en")
+	b.AddMessages("en", map[string]string{"hello": "hello %s"})
+	b.AddMessages("zh", map[string]string{"hello": "你好 %s"})
+	old := Default()
+	SetDefault(b)
+	defer SetDefault(old)
+
+	r := gin.New()
+	r.Use(Middleware(nil))
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, T(c.Request.Context(), "hello", FromContext(c)))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en-US;q=0.8")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, "hello en", w.Body.String())
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, "你好 zh", w.Body.String())
+}
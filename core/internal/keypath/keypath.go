@@ -0,0 +1,307 @@
+// Package keypath maps the flat "a.b[0].c" keys of the line based formats, properties and ini,
+// to the nested maps and lists of the structured ones, and back.
+// The values of these formats are untyped, they are kept as strings and converted by Decode
+// to the types of the target.
+package keypath
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Set sets the value of key in m, "a.b" is the key b of the map a, "a[0]" the first item of the list a.
+// The lists are kept as maps of "[n]" keys until Lists is called.
+func Set(m map[string]interface{}, key string, v interface{}) error {
+	segs := split(key)
+	if len(segs) == 0 {
+		return fmt.Errorf("empty key")
+	}
+	for i, seg := range segs[:len(segs)-1] {
+		next, ok := m[seg]
+		if !ok {
+			sub := make(map[string]interface{})
+			m[seg] = sub
+			m = sub
+			continue
+		}
+		if m, ok = next.(map[string]interface{}); !ok {
+			return fmt.Errorf("key %s conflicts with %s", key, join(segs[:i+1]))
+		}
+	}
+	last := segs[len(segs)-1]
+	if _, ok := m[last].(map[string]interface{}); ok {
+		return fmt.Errorf("key %s conflicts with its nested keys", key)
+	}
+	m[last] = v
+	return nil
+}
+
+// split splits "a.b[0][1]" into a, b, [0], [1].
+func split(key string) []string {
+	var segs []string
+	for _, part := range strings.Split(key, ".") {
+		for {
+			open := strings.IndexByte(part, '[')
+			end := strings.IndexByte(part, ']')
+			if open < 0 || end < open {
+				break
+			}
+			if _, err := strconv.Atoi(part[open+1 : end]); err != nil {
+				break
+			}
+			if open > 0 {
+				segs = append(segs, part[:open])
+			}
+			segs = append(segs, part[open:end+1])
+			part = part[end+1:]
+		}
+		if part != "" {
+			segs = append(segs, part)
+		}
+	}
+	return segs
+}
+
+func join(segs []string) string {
+	var b strings.Builder
+	for i, seg := range segs {
+		if i > 0 && !isIndex(seg) {
+			b.WriteByte('.')
+		}
+		b.WriteString(seg)
+	}
+	return b.String()
+}
+
+func isIndex(seg string) bool {
+	return len(seg) > 2 && seg[0] == '[' && seg[len(seg)-1] == ']'
+}
+
+// Lists converts the maps of "[n]" keys set by Set to lists, ordered by index.
+func Lists(v interface{}) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	for k, item := range m {
+		m[k] = Lists(item)
+	}
+	if len(m) == 0 {
+		return m
+	}
+	idx := make([]int, 0, len(m))
+	for k := range m {
+		if !isIndex(k) {
+			return m
+		}
+		n, _ := strconv.Atoi(k[1 : len(k)-1])
+		idx = append(idx, n)
+	}
+	sort.Ints(idx)
+	list := make([]interface{}, len(idx))
+	for i, n := range idx {
+		list[i] = m["["+strconv.Itoa(n)+"]"]
+	}
+	return list
+}
+
+// Walk calls fn with the flat key of every leaf of v, in key order.
+func Walk(prefix string, v interface{}, fn func(key string, v interface{})) {
+	switch vt := v.(type) {
+	case map[string]interface{}:
+		if len(vt) == 0 && prefix != "" {
+			return
+		}
+		keys := make([]string, 0, len(vt))
+		for k := range vt {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			Walk(key, vt[k], fn)
+		}
+	case []interface{}:
+		for i, item := range vt {
+			Walk(fmt.Sprintf("%s[%d]", prefix, i), item, fn)
+		}
+	default:
+		fn(prefix, v)
+	}
+}
+
+// Format returns the text of a leaf, quoted is true when the text would not be read back as it is.
+func Format(v interface{}) (text string, quoted bool) {
+	switch vt := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		if strings.TrimSpace(vt) != vt || Quoted(vt) {
+			return vt, true
+		}
+		return vt, false
+	case float64:
+		if vt == math.Trunc(vt) && math.Abs(vt) < 1e15 {
+			return strconv.FormatInt(int64(vt), 10), false
+		}
+		return strconv.FormatFloat(vt, 'f', -1, 64), false
+	case json.Number:
+		return vt.String(), false
+	}
+	return fmt.Sprint(v), false
+}
+
+// Quoted reports whether s is enclosed in double quotes.
+func Quoted(s string) bool {
+	return len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"'
+}
+
+// ToMap returns v as the map of its json form.
+func ToMap(v interface{}) (map[string]interface{}, error) {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%T is not an object", v)
+	}
+	return m, nil
+}
+
+// Decode stores m in v: merged into a *map[string]interface{}, through json for the other types
+// after the strings read for their bool and number fields are converted.
+func Decode(m map[string]interface{}, v interface{}) error {
+	if p, ok := v.(*map[string]interface{}); ok && p != nil {
+		if *p == nil {
+			*p = make(map[string]interface{}, len(m))
+		}
+		for k, item := range m {
+			(*p)[k] = item
+		}
+		return nil
+	}
+	data, err := json.Marshal(typed(m, reflect.TypeOf(v)))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// typed converts the strings of v to the bool and number kinds of t, the fields are found as encoding/json does.
+// The strings that do not convert are kept, json reports them.
+func typed(v interface{}, t reflect.Type) interface{} {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || reflect.PtrTo(t).Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return v
+	}
+	switch vt := v.(type) {
+	case string:
+		return typedString(vt, t)
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return v
+		}
+		list := make([]interface{}, len(vt))
+		for i, item := range vt {
+			list[i] = typed(item, t.Elem())
+		}
+		return list
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(vt))
+		for k, item := range vt {
+			switch t.Kind() {
+			case reflect.Map:
+				res[k] = typed(item, t.Elem())
+			case reflect.Struct:
+				if ft, ok := fieldType(t, k); ok {
+					res[k] = typed(item, ft)
+					continue
+				}
+				res[k] = item
+			default:
+				res[k] = item
+			}
+		}
+		return res
+	}
+	return v
+}
+
+func typedString(s string, t reflect.Type) interface{} {
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// fieldType returns the type of the field of the struct t encoding/json decodes key into.
+func fieldType(t reflect.Type, key string) (reflect.Type, bool) {
+	var folded reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if found, ok := fieldType(ft, key); ok {
+					return found, true
+				}
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		if name == key {
+			return sf.Type, true
+		}
+		if folded == nil && strings.EqualFold(name, key) {
+			folded = sf.Type
+		}
+	}
+	return folded, folded != nil
+}
@@ -3,6 +3,8 @@ package ginMiddleWare
 import (
 	"bytes"
 	"io"
+	"net/http"
+	"reflect"
 
 	"github.com/gin-gonic/gin"
 )
@@ -44,3 +46,110 @@ func NewCachedResponseWriter(w gin.ResponseWriter) *cachedResponseWriter {
 func (w *cachedResponseWriter) Write(p []byte) (int, error) {
 	return w.writer.Write(p)
 }
+
+// BufferedResponseWriter keeps status, headers and body in memory, nothing
+// reaches the client until Commit is called.
+type BufferedResponseWriter struct {
+	gin.ResponseWriter
+
+	header  http.Header
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func NewBufferedResponseWriter(w gin.ResponseWriter) *BufferedResponseWriter {
+	return &BufferedResponseWriter{
+		ResponseWriter: w,
+		header:         w.Header().Clone(),
+		status:         http.StatusOK,
+	}
+}
+
+func (w *BufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *BufferedResponseWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *BufferedResponseWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *BufferedResponseWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.body.Write(p)
+}
+
+func (w *BufferedResponseWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *BufferedResponseWriter) Status() int {
+	return w.status
+}
+
+func (w *BufferedResponseWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *BufferedResponseWriter) Written() bool {
+	return w.written
+}
+
+// Flush is a no-op, the response is only sent by Commit.
+func (w *BufferedResponseWriter) Flush() {}
+
+// Body returns the buffered body.
+func (w *BufferedResponseWriter) Body() []byte {
+	return w.body.Bytes()
+}
+
+// Reset drops everything buffered so far.
+func (w *BufferedResponseWriter) Reset() {
+	w.header = w.ResponseWriter.Header().Clone()
+	w.status = http.StatusOK
+	w.written = false
+	w.body.Reset()
+}
+
+// Commit writes the buffered response to the underlying writer.
+func (w *BufferedResponseWriter) Commit() error {
+	dst := w.ResponseWriter.Header()
+	for k := range dst {
+		if _, ok := w.header[k]; !ok {
+			dst.Del(k)
+		}
+	}
+	for k, vs := range w.header {
+		dst[k] = vs
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.body.Len() == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.body.Bytes())
+	return err
+}
+
+// AddedHeader returns the headers of after that are new or changed since before,
+// i.e. the ones set by the handlers that ran in between.
+func AddedHeader(before, after http.Header) http.Header {
+	added := http.Header{}
+	for k, vs := range after {
+		if old, ok := before[k]; ok && reflect.DeepEqual(old, vs) {
+			continue
+		}
+		added[k] = append([]string(nil), vs...)
+	}
+	return added
+}
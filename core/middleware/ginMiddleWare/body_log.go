@@ -0,0 +1,353 @@
+package ginMiddleWare
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RedactedValue replaces every redacted value.
+	RedactedValue = "******"
+
+	defaultMaxBodyBytes = 4 << 10
+)
+
+var (
+	// DefaultBodyContentTypes content types whose bodies are logged by default.
+	DefaultBodyContentTypes = []string{"application/json", "application/x-www-form-urlencoded", "application/xml", "text/"}
+	// DefaultRedactFields json keys redacted at any depth by default.
+	DefaultRedactFields = []string{"password", "passwd", "secret", "token", "access_token", "refresh_token", "authorization"}
+	// DefaultRedactHeaders headers redacted by default.
+	DefaultRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+)
+
+// BodyPolicy decides what of a request/response body reaches the log.
+type BodyPolicy struct {
+	// Disable skips body logging
+	Disable bool
+	// MaxBytes bodies are captured up to MaxBytes, default 4KB
+	MaxBytes int
+	// ContentTypes allow-list, prefix matched, default DefaultBodyContentTypes. Bodies without Content-Type are not logged.
+	// multipart and binary bodies are skipped unless listed here.
+	ContentTypes []string
+	// RedactFields json paths whose values are replaced with RedactedValue.
+	// A plain key ("password") matches at any depth, a dotted path ("data.items.*.secret")
+	// matches from the root with "*" matching any key or array index.
+	// Default DefaultRedactFields.
+	RedactFields []string
+	// RedactQuery query params redacted in the logged query string,
+	// default DefaultRedactFields plus the last segment of every RedactFields path
+	RedactQuery []string
+	// LogHeaders also logs request headers
+	LogHeaders bool
+	// RedactHeaders headers redacted when LogHeaders, default DefaultRedactHeaders
+	RedactHeaders []string
+}
+
+// bodyLogPolicy is a BodyPolicy with defaults applied and lookups prepared.
+type bodyLogPolicy struct {
+	disable       bool
+	maxBytes      int
+	contentTypes  []string
+	redactKeys    map[string]bool
+	redactPaths   [][]string
+	redactRegexp  *regexp.Regexp
+	redactQuery   map[string]bool
+	logHeaders    bool
+	redactHeaders map[string]bool
+}
+
+func newBodyLogPolicy(p *BodyPolicy, disableBody bool) *bodyLogPolicy {
+	if p == nil {
+		p = &BodyPolicy{}
+	}
+	bp := &bodyLogPolicy{
+		disable:       p.Disable || disableBody,
+		maxBytes:      p.MaxBytes,
+		contentTypes:  p.ContentTypes,
+		redactKeys:    map[string]bool{},
+		redactQuery:   map[string]bool{},
+		logHeaders:    p.LogHeaders,
+		redactHeaders: map[string]bool{},
+	}
+	if bp.maxBytes <= 0 {
+		bp.maxBytes = defaultMaxBodyBytes
+	}
+	if len(bp.contentTypes) == 0 {
+		bp.contentTypes = DefaultBodyContentTypes
+	}
+	fields := p.RedactFields
+	if len(fields) == 0 {
+		fields = DefaultRedactFields
+	}
+	var names []string
+	for _, f := range fields {
+		path := strings.Split(f, ".")
+		if len(path) == 1 {
+			bp.redactKeys[strings.ToLower(f)] = true
+		} else {
+			bp.redactPaths = append(bp.redactPaths, path)
+		}
+		// the last segment is used by the fallback of bodies that are not valid json
+		if last := path[len(path)-1]; last != "*" {
+			names = append(names, regexp.QuoteMeta(last))
+		}
+	}
+	if len(names) > 0 {
+		bp.redactRegexp = regexp.MustCompile(`(?i)("(?:` + strings.Join(names, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+	}
+	query := p.RedactQuery
+	if len(query) == 0 {
+		query = append(query, DefaultRedactFields...)
+		for _, f := range fields {
+			path := strings.Split(f, ".")
+			query = append(query, path[len(path)-1])
+		}
+	}
+	for _, q := range query {
+		bp.redactQuery[strings.ToLower(q)] = true
+	}
+	headers := p.RedactHeaders
+	if len(headers) == 0 {
+		headers = DefaultRedactHeaders
+	}
+	for _, h := range headers {
+		bp.redactHeaders[http.CanonicalHeaderKey(h)] = true
+	}
+	return bp
+}
+
+// allowContentType reports whether bodies of contentType are logged, a body without Content-Type is not.
+func (p *bodyLogPolicy) allowContentType(contentType string) bool {
+	mediaType := p.mediaType(contentType)
+	if mediaType == "" {
+		return false
+	}
+	for _, allowed := range p.contentTypes {
+		if strings.HasPrefix(mediaType, strings.ToLower(allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *bodyLogPolicy) mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// body renders a captured body for the log.
+func (p *bodyLogPolicy) body(contentType string, buf *limitedBuffer) string {
+	if buf.total == 0 {
+		return ""
+	}
+	if !p.allowContentType(contentType) {
+		return fmt.Sprintf("<omitted %s body, %d bytes>", contentType, buf.total)
+	}
+	data := buf.Bytes()
+	if p.mediaType(contentType) == "application/x-www-form-urlencoded" {
+		data = p.redactForm(data)
+	} else {
+		data = p.redactBody(data, buf.truncated)
+	}
+	if buf.truncated {
+		return fmt.Sprintf("%s...<truncated, %d bytes total>", data, buf.total)
+	}
+	return string(data)
+}
+
+func (p *bodyLogPolicy) redactBody(data []byte, truncated bool) []byte {
+	if len(p.redactKeys) == 0 && len(p.redactPaths) == 0 {
+		return data
+	}
+	if !truncated {
+		var v interface{}
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err == nil {
+			v = p.redactValue(v, nil)
+			if out, err := json.Marshal(v); err == nil {
+				return out
+			}
+		}
+	}
+	// not (complete) json: redact "key": value pairs textually
+	if p.redactRegexp != nil {
+		return p.redactRegexp.ReplaceAll(data, []byte(`${1}"`+RedactedValue+`"`))
+	}
+	return data
+}
+
+// redactForm masks the redacted fields of a form body, matched as the top level keys of a json body.
+func (p *bodyLogPolicy) redactForm(data []byte) []byte {
+	values, err := url.ParseQuery(string(data))
+	if err != nil {
+		return []byte("<unparseable form omitted>")
+	}
+	redacted := false
+	for k, vs := range values {
+		if p.matchPath([]string{k}) || p.redactQuery[strings.ToLower(k)] {
+			for i := range vs {
+				vs[i] = RedactedValue
+			}
+			redacted = true
+		}
+	}
+	if !redacted {
+		return data
+	}
+	return []byte(values.Encode())
+}
+
+func (p *bodyLogPolicy) redactValue(v interface{}, path []string) interface{} {
+	if p.matchPath(path) {
+		return RedactedValue
+	}
+	switch vv := v.(type) {
+	case map[string]interface{}:
+		for k, child := range vv {
+			vv[k] = p.redactValue(child, append(path, k))
+		}
+	case []interface{}:
+		for i, child := range vv {
+			vv[i] = p.redactValue(child, append(path, fmt.Sprint(i)))
+		}
+	}
+	return v
+}
+
+func (p *bodyLogPolicy) matchPath(path []string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	if p.redactKeys[strings.ToLower(path[len(path)-1])] {
+		return true
+	}
+	for _, rule := range p.redactPaths {
+		if len(rule) != len(path) {
+			continue
+		}
+		matched := true
+		for i := range rule {
+			if rule[i] != "*" && !strings.EqualFold(rule[i], path[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// query returns the raw query with secrets redacted.
+func (p *bodyLogPolicy) query(rawQuery string) string {
+	if rawQuery == "" || len(p.redactQuery) == 0 {
+		return rawQuery
+	}
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "<unparseable query omitted>"
+	}
+	redacted := false
+	for k, vs := range values {
+		if p.redactQuery[strings.ToLower(k)] {
+			for i := range vs {
+				vs[i] = RedactedValue
+			}
+			redacted = true
+		}
+	}
+	if !redacted {
+		return rawQuery
+	}
+	return values.Encode()
+}
+
+// headers returns the request headers with secrets redacted.
+func (p *bodyLogPolicy) headers(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, vs := range h {
+		if p.redactHeaders[http.CanonicalHeaderKey(k)] {
+			out[k] = RedactedValue
+			continue
+		}
+		out[k] = strings.Join(vs, ",")
+	}
+	return out
+}
+
+// limitedBuffer keeps the first limit bytes written to it and counts the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	total     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.total += len(p)
+	if room := b.limit - b.Buffer.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+			b.truncated = true
+		} else {
+			b.Buffer.Write(p)
+		}
+	} else if len(p) > 0 {
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+// limitedReadCloser captures up to limit bytes of what is read from the body.
+type limitedReadCloser struct {
+	io.ReadCloser
+	cache  *limitedBuffer
+	reader io.Reader
+}
+
+func newLimitedReadCloser(rc io.ReadCloser, limit int) *limitedReadCloser {
+	c := &limitedReadCloser{ReadCloser: rc, cache: &limitedBuffer{limit: limit}}
+	c.reader = io.TeeReader(rc, c.cache)
+	return c
+}
+
+func (rc *limitedReadCloser) Read(p []byte) (int, error) {
+	return rc.reader.Read(p)
+}
+
+// limitedResponseWriter captures up to limit bytes of the response body.
+type limitedResponseWriter struct {
+	gin.ResponseWriter
+	cache *limitedBuffer
+}
+
+func newLimitedResponseWriter(w gin.ResponseWriter, limit int) *limitedResponseWriter {
+	return &limitedResponseWriter{ResponseWriter: w, cache: &limitedBuffer{limit: limit}}
+}
+
+func (w *limitedResponseWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	_, _ = w.cache.Write(p[:n])
+	return n, err
+}
+
+func (w *limitedResponseWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	_, _ = w.cache.Write([]byte(s[:n]))
+	return n, err
+}
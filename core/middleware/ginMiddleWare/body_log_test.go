@@ -0,0 +1,92 @@
+package ginMiddleWare
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+func TestGinZapBodyPolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var out bytes.Buffer
+	w := logx.NewWriter(&out)
+
+	r := gin.New()
+	r.Use(GinZapWithConfig(w, &Config{
+		Body: &BodyPolicy{
+			MaxBytes:     64,
+			RedactFields: []string{"password", "data.*.secret"},
+			LogHeaders:   true,
+		},
+		RouteBody: map[string]*BodyPolicy{"/upload": {Disable: true}},
+	}))
+	r.POST("/login", func(c *gin.Context) {
+		body, _ := c.GetRawData()
+		c.Data(http.StatusOK, "application/json", body)
+	})
+	r.POST("/upload", func(c *gin.Context) {
+		c.String(http.StatusOK, "uploaded")
+	})
+	r.GET("/big", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("x", 200))
+	})
+	r.GET("/file", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/octet-stream", []byte("binary"))
+	})
+
+	serve := func(req *http.Request) string {
+		out.Reset()
+		r.ServeHTTP(httptest.NewRecorder(), req)
+		return out.String()
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/login?user=a&token=abc",
+		strings.NewReader(`{"user":"a","password":"p@ss","data":[{"secret":"s1","id":1}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer xyz")
+	log := serve(req)
+	for _, secret := range []string{"p@ss", "s1", "abc", "xyz"} {
+		assert.NotContains(t, log, secret)
+	}
+	assert.Contains(t, log, RedactedValue)
+	assert.Contains(t, log, `\"id\":1`)
+
+	log = serve(httptest.NewRequest(http.MethodGet, "/big", nil))
+	assert.Contains(t, log, "truncated, 200 bytes total")
+	assert.NotContains(t, log, strings.Repeat("x", 65))
+
+	log = serve(httptest.NewRequest(http.MethodGet, "/file", nil))
+	assert.Contains(t, log, "omitted application/octet-stream body")
+	assert.NotContains(t, log, "binary\"")
+
+	log = serve(httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("raw")))
+	assert.NotContains(t, log, "request-body")
+}
+
+func TestRedactTruncatedBody(t *testing.T) {
+	p := newBodyLogPolicy(&BodyPolicy{MaxBytes: 30}, false)
+	buf := &limitedBuffer{limit: 30}
+	_, _ = buf.Write([]byte(`{"name":"n","password":"secret-value-that-is-long"}`))
+	assert.NotContains(t, p.body("application/json", buf), "secret-value")
+}
+
+func TestRedactFormBody(t *testing.T) {
+	p := newBodyLogPolicy(nil, false)
+	buf := &limitedBuffer{limit: 1024}
+	_, _ = buf.Write([]byte("username=a&password=hunter2&token=t0k"))
+	log := p.body("application/x-www-form-urlencoded; charset=utf-8", buf)
+	assert.NotContains(t, log, "hunter2")
+	assert.NotContains(t, log, "t0k")
+	assert.Contains(t, log, "username=a")
+
+	buf = &limitedBuffer{limit: 1024}
+	_, _ = buf.Write([]byte("password=hunter2"))
+	assert.Equal(t, "<omitted  body, 16 bytes>", p.body("", buf))
+	assert.Equal(t, "", p.body("", &limitedBuffer{limit: 1024}))
+}
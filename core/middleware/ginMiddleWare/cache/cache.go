@@ -0,0 +1,290 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/middleware/ginMiddleWare"
+
+	"github.com/gin-gonic/gin"
+)
+
+// uncachedHeaders are per request or per user, they are never stored.
+var uncachedHeaders = []string{"Set-Cookie", "X-Request-Id", "X-Trace-Id", "X-Cache"}
+
+// Cache caches GET responses of the routes it is installed on.
+//
+//	cacher := cache.New(cache.NewMemoryStore(1000))
+//	r.GET("/api/v1/enums", cacher.Handler(time.Minute, cache.Tags("enum")), listEnums)
+//	r.PUT("/api/v1/enums/:id", cacher.Invalidate("enum"), updateEnum)
+type Cache struct {
+	store       Store
+	varyHeaders []string
+	onError     func(c *gin.Context, err error)
+}
+
+// Option is a Cache option.
+type Option func(*Cache)
+
+// VaryHeaders adds request headers to every cache key, e.g. Accept-Language.
+func VaryHeaders(headers ...string) Option {
+	return func(c *Cache) {
+		c.varyHeaders = append(c.varyHeaders, headers...)
+	}
+}
+
+// OnError is called when the store fails, the request is served uncached. Default records the error in c.Errors.
+func OnError(fn func(c *gin.Context, err error)) Option {
+	return func(c *Cache) {
+		c.onError = fn
+	}
+}
+
+// New create a Cache on the store.
+func New(store Store, opts ...Option) *Cache {
+	c := &Cache{
+		store: store,
+		onError: func(c *gin.Context, err error) {
+			_ = c.Error(err)
+		},
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+// RouteOption is a per route option of Handler.
+type RouteOption func(*routeOptions)
+
+type routeOptions struct {
+	tags        []string
+	varyHeaders []string
+}
+
+// Tags indexes the cached responses under tags so that Invalidate can purge them.
+// A tag ending with "::name" takes the route param, e.g. "form_view::id" becomes "form_view:42".
+func Tags(tags ...string) RouteOption {
+	return func(o *routeOptions) {
+		o.tags = append(o.tags, tags...)
+	}
+}
+
+// Vary adds request headers to the cache key of this route.
+func Vary(headers ...string) RouteOption {
+	return func(o *routeOptions) {
+		o.varyHeaders = append(o.varyHeaders, headers...)
+	}
+}
+
+// Handler caches successful GET/HEAD responses of the route for ttl.
+// Responses carry an ETag, requests whose If-None-Match matches get 304.
+// Only the headers set by the handler are stored, without cookies and request ids.
+// Requests with Authorization bypass the cache unless the key varies on it.
+func (ca *Cache) Handler(ttl time.Duration, opts ...RouteOption) gin.HandlerFunc {
+	o := &routeOptions{varyHeaders: ca.varyHeaders}
+	for _, opt := range opts {
+		opt(o)
+	}
+	varyAuth := false
+	for _, h := range o.varyHeaders {
+		if strings.EqualFold(h, "Authorization") {
+			varyAuth = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+		if strings.Contains(c.GetHeader("Cache-Control"), "no-cache") {
+			c.Next()
+			return
+		}
+		if !varyAuth && c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		key := cacheKey(c, o.varyHeaders)
+		entry, ok, err := ca.store.Get(ctx, key)
+		if err != nil {
+			ca.onError(c, err)
+		}
+		if ok {
+			writeEntry(c, entry, "HIT")
+			c.Abort()
+			return
+		}
+
+		before := c.Writer.Header().Clone()
+		bw := ginMiddleWare.NewBufferedResponseWriter(c.Writer)
+		next(c, bw)
+
+		if bw.Status() != http.StatusOK || strings.Contains(bw.Header().Get("Cache-Control"), "no-store") {
+			if err := bw.Commit(); err != nil {
+				_ = c.Error(err)
+			}
+			return
+		}
+
+		added := ginMiddleWare.AddedHeader(before, bw.Header())
+		entry = &Entry{
+			Status: bw.Status(),
+			Header: added.Clone(),
+			Body:   append([]byte(nil), bw.Body()...),
+			ETag:   etag(bw.Body()),
+		}
+		for _, h := range uncachedHeaders {
+			entry.Header.Del(h)
+		}
+		entry.Header.Set("ETag", entry.ETag)
+		if err := ca.store.Set(ctx, key, entry, ttl, resolveTags(c, o.tags)); err != nil {
+			ca.onError(c, err)
+		}
+		// this response keeps all the headers set for it
+		miss := *entry
+		miss.Header = added
+		miss.Header.Set("ETag", entry.ETag)
+		writeEntry(c, &miss, "MISS")
+	}
+}
+
+// next runs the rest of the chain on bw, the writer is restored even if a handler panics.
+func next(c *gin.Context, bw *ginMiddleWare.BufferedResponseWriter) {
+	c.Writer = bw
+	defer func() { c.Writer = bw.ResponseWriter }()
+	c.Next()
+}
+
+// Invalidate purges the tags after the handler succeeded (status < 400).
+func (ca *Cache) Invalidate(tags ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if c.Writer.Status() >= http.StatusBadRequest {
+			return
+		}
+		if err := ca.store.InvalidateTags(c.Request.Context(), resolveTags(c, tags)...); err != nil {
+			ca.onError(c, err)
+		}
+	}
+}
+
+// Purge removes the cached responses under tags.
+func (ca *Cache) Purge(ctx context.Context, tags ...string) error {
+	return ca.store.InvalidateTags(ctx, tags...)
+}
+
+func writeEntry(c *gin.Context, entry *Entry, status string) {
+	h := c.Writer.Header()
+	for k, vs := range entry.Header {
+		h[k] = vs
+	}
+	h.Set("X-Cache", status)
+	if etagMatch(c.GetHeader("If-None-Match"), entry.ETag) {
+		h.Del("Content-Length")
+		h.Del("Content-Type")
+		c.Writer.WriteHeader(http.StatusNotModified)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	c.Writer.WriteHeader(entry.Status)
+	if c.Request.Method == http.MethodHead {
+		c.Writer.WriteHeaderNow()
+		return
+	}
+	_, _ = c.Writer.Write(entry.Body)
+}
+
+// cacheKey route template + normalized query + selected headers.
+func cacheKey(c *gin.Context, varyHeaders []string) string {
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+	var b strings.Builder
+	b.WriteString(route)
+	b.WriteByte('\n')
+	b.WriteString(normalizeQuery(c.Request.URL.Query()))
+	for _, h := range varyHeaders {
+		b.WriteByte('\n')
+		b.WriteString(http.CanonicalHeaderKey(h))
+		b.WriteByte('=')
+		b.WriteString(c.GetHeader(h))
+	}
+	for _, p := range c.Params {
+		b.WriteByte('\n')
+		b.WriteString(p.Key)
+		b.WriteByte('=')
+		b.WriteString(p.Value)
+	}
+	sum := sha256.Sum256([]byte(b.String()))
+	return route + ":" + hex.EncodeToString(sum[:16])
+}
+
+// normalizeQuery sorts the keys and the values so that ?b=1&a=2 and ?a=2&b=1 share a key.
+func normalizeQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		vs := append([]string(nil), q[k]...)
+		sort.Strings(vs)
+		for _, v := range vs {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(k))
+			b.WriteByte('=')
+			b.WriteString(url.QueryEscape(v))
+		}
+	}
+	return b.String()
+}
+
+func resolveTags(c *gin.Context, tags []string) []string {
+	resolved := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		resolved = append(resolved, expandParams(c, tag))
+	}
+	return resolved
+}
+
+// expandParams replaces the ":name" suffix after "::" with the route param value.
+func expandParams(c *gin.Context, tag string) string {
+	idx := strings.Index(tag, "::")
+	if idx < 0 {
+		return tag
+	}
+	name := tag[idx+2:]
+	return tag[:idx+1] + c.Param(name)
+}
+
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+func etagMatch(ifNoneMatch, tag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cacher := New(NewMemoryStore(10))
+	calls := 0
+
+	r := gin.New()
+	r.GET("/enums/:kind", cacher.Handler(time.Minute, Tags("enum::kind")), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"kind": c.Param("kind"), "page": c.Query("page")})
+	})
+	r.PUT("/enums/:kind", cacher.Invalidate("enum::kind"), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	do := func(method, target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("GET", "/enums/status?page=1&size=10", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	etag := w.Header().Get("ETag")
+	assert.NotEmpty(t, etag)
+	body := w.Body.String()
+
+	// same query in another order hits the cache
+	w = do("GET", "/enums/status?size=10&page=1", nil)
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Equal(t, body, w.Body.String())
+	assert.Equal(t, 1, calls)
+
+	w = do("GET", "/enums/status?page=1&size=10", http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	// another kind is cached separately and survives the invalidation
+	do("GET", "/enums/type", nil)
+	assert.Equal(t, 2, calls)
+
+	assert.Equal(t, http.StatusNoContent, do("PUT", "/enums/status", nil).Code)
+	w = do("GET", "/enums/status?page=1&size=10", nil)
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, 3, calls)
+	w = do("GET", "/enums/type", nil)
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Equal(t, 3, calls)
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	s := NewMemoryStore(2)
+	ctx := httptest.NewRequest("GET", "/", nil).Context()
+	for _, k := range []string{"a", "b", "c"} {
+		_ = s.Set(ctx, k, &Entry{Status: http.StatusOK}, time.Minute, []string{"t"})
+	}
+	assert.Equal(t, 2, s.Len())
+	_, ok, _ := s.Get(ctx, "a")
+	assert.False(t, ok)
+
+	_ = s.Set(ctx, "d", &Entry{}, -time.Second, nil)
+	_, ok, _ = s.Get(ctx, "d")
+	assert.False(t, ok)
+
+	_ = s.InvalidateTags(ctx, "t")
+	assert.Equal(t, 0, s.Len())
+}
+
+func TestCacheHandlerHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cacher := New(NewMemoryStore(10))
+	calls := 0
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Header("X-Request-Id", c.GetHeader("X-Request-Id"))
+		c.Header("Access-Control-Allow-Origin", c.GetHeader("Origin"))
+		c.Next()
+	})
+	handler := func(c *gin.Context) {
+		calls++
+		c.SetCookie("session", c.GetHeader("X-Request-Id"), 60, "/", "", false, true)
+		c.Header("X-Handler", "1")
+		c.String(http.StatusOK, "user %s", c.GetHeader("Authorization"))
+	}
+	r.GET("/public", cacher.Handler(time.Minute), handler)
+	r.GET("/private", cacher.Handler(time.Minute, Vary("Authorization")), handler)
+
+	do := func(target, reqID, origin, auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-Request-Id", reqID)
+		req.Header.Set("Origin", origin)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("/public", "r1", "https://a.example.com", "")
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Contains(t, w.Header().Get("Set-Cookie"), "session=r1")
+
+	w = do("/public", "r2", "https://b.example.com", "")
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Equal(t, "1", w.Header().Get("X-Handler"))
+	assert.Equal(t, "r2", w.Header().Get("X-Request-Id"))
+	assert.Equal(t, "https://b.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Set-Cookie"))
+	assert.Equal(t, 1, calls)
+
+	// not cached when the key does not vary on Authorization
+	w = do("/public", "r3", "", "Bearer alice")
+	assert.Empty(t, w.Header().Get("X-Cache"))
+	assert.Equal(t, "user Bearer alice", w.Body.String())
+	w = do("/public", "r4", "", "Bearer bob")
+	assert.Equal(t, "user Bearer bob", w.Body.String())
+	assert.Equal(t, 3, calls)
+
+	do("/private", "r5", "", "Bearer alice")
+	w = do("/private", "r6", "", "Bearer alice")
+	assert.Equal(t, "HIT", w.Header().Get("X-Cache"))
+	assert.Equal(t, "user Bearer alice", w.Body.String())
+	w = do("/private", "r7", "", "Bearer bob")
+	assert.Equal(t, "MISS", w.Header().Get("X-Cache"))
+	assert.Equal(t, "user Bearer bob", w.Body.String())
+	assert.Equal(t, 5, calls)
+}
+
+func TestCacheHandlerPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.GET("/panic", New(NewMemoryStore(10)).Handler(time.Minute), func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
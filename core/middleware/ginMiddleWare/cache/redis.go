@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/redis_tool"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisStore stores responses in redis, tags are kept as redis sets of keys.
+type RedisStore struct {
+	client *redis_tool.Redis
+	prefix string
+}
+
+var _ Store = (*RedisStore)(nil)
+
+// NewRedisStore create a redis store, keys are prefixed with prefix (default "httpcache:").
+func NewRedisStore(client *redis_tool.Redis, prefix string) *RedisStore {
+	if prefix == "" {
+		prefix = "httpcache:"
+	}
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (*Entry, bool, error) {
+	data, err := s.client.Read.Get(ctx, s.prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	entry := &Entry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, false, err
+	}
+	return entry, true, nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key string, entry *Entry, ttl time.Duration, tags []string) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := s.client.Write.Set(ctx, s.prefix+key, data, ttl).Err(); err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		tagKey := s.tagKey(tag)
+		if err := s.client.Write.SAdd(ctx, tagKey, key).Err(); err != nil {
+			return err
+		}
+		// the tag set must outlive every key it indexes
+		if current, err := s.client.Write.TTL(ctx, tagKey).Result(); err == nil && current < ttl {
+			s.client.Write.Expire(ctx, tagKey, ttl)
+		}
+	}
+	return nil
+}
+
+func (s *RedisStore) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		tagKey := s.tagKey(tag)
+		keys, err := s.client.Write.SMembers(ctx, tagKey).Result()
+		if err != nil {
+			return err
+		}
+		for i := range keys {
+			keys[i] = s.prefix + keys[i]
+		}
+		// delete one by one, keys of a tag may live on different cluster slots
+		for _, k := range keys {
+			if err := s.client.Write.Del(ctx, k).Err(); err != nil {
+				return err
+			}
+		}
+		if err := s.client.Write.Del(ctx, tagKey).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *RedisStore) tagKey(tag string) string {
+	return s.prefix + "tag:" + tag
+}
@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Entry is a cached response.
+type Entry struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+	ETag   string      `json:"etag"`
+}
+
+// Store keeps cached responses.
+type Store interface {
+	// Get returns the entry of key, ok is false on miss.
+	Get(ctx context.Context, key string) (entry *Entry, ok bool, err error)
+	// Set stores the entry for ttl and indexes it under tags.
+	Set(ctx context.Context, key string, entry *Entry, ttl time.Duration, tags []string) error
+	// InvalidateTags removes every entry indexed under any of tags.
+	InvalidateTags(ctx context.Context, tags ...string) error
+}
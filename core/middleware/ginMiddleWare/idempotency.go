@@ -0,0 +1,178 @@
+package ginMiddleWare
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+	"github.com/kweaver-ai/idrm-go-frame/core/redis_tool"
+	"github.com/kweaver-ai/idrm-go-frame/core/transport/rest/ginx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// IdempotencyKeyHeader is the default request header carrying the idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set on responses replayed from the store.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// IdempotencyConfig is config setting for Idempotency.
+type IdempotencyConfig struct {
+	Client redis.Cmdable
+	// Header request header carrying the key, default Idempotency-Key
+	Header string
+	// KeyPrefix redis key prefix, default idempotency:
+	KeyPrefix string
+	// TTL how long a stored response can be replayed, default 24h
+	TTL time.Duration
+	// LockSeconds expire of the in-flight lock, should be longer than the slowest handler, default 30s
+	LockSeconds int
+	// Methods only these methods are idempotent-checked, default POST
+	Methods []string
+}
+
+// idempotentRecord is the response stored for a key.
+type idempotentRecord struct {
+	BodyHash string      `json:"body_hash"`
+	Status   int         `json:"status"`
+	Header   http.Header `json:"header"`
+	Body     []byte      `json:"body"`
+}
+
+// Idempotency replays the stored response for requests repeating an Idempotency-Key.
+//
+// The first request with a key runs the handler under a RedisLock and stores
+// status, the headers set by the handler and body. Later requests with the same
+// key and the same body get the stored response, a different body or a request
+// still in flight get 409.
+// 5xx responses are not stored, so the client can retry them.
+func Idempotency(conf *IdempotencyConfig) gin.HandlerFunc {
+	header := conf.Header
+	if header == "" {
+		header = IdempotencyKeyHeader
+	}
+	prefix := conf.KeyPrefix
+	if prefix == "" {
+		prefix = "idempotency:"
+	}
+	ttl := conf.TTL
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	lockSeconds := conf.LockSeconds
+	if lockSeconds <= 0 {
+		lockSeconds = 30
+	}
+	methods := map[string]bool{http.MethodPost: true}
+	if len(conf.Methods) > 0 {
+		methods = make(map[string]bool, len(conf.Methods))
+		for _, m := range conf.Methods {
+			methods[m] = true
+		}
+	}
+
+	return func(c *gin.Context) {
+		idemKey := c.GetHeader(header)
+		if idemKey == "" || !methods[c.Request.Method] {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			ginx.AbortResponseWithCode(c, http.StatusBadRequest, agerrors.NewCode(agcodes.CodeInvalidParameter, err.Error()))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+		bodyHash := hex.EncodeToString(sum[:])
+
+		ctx := c.Request.Context()
+		key := prefix + c.Request.Method + ":" + c.FullPath() + ":" + idemKey
+		if replayed := replayIdempotent(c, conf.Client, key, bodyHash); replayed {
+			return
+		}
+
+		lock := redis_tool.NewRedisLock(conf.Client, key+":lock")
+		lock.SetExpire(lockSeconds)
+		locked, err := lock.LockCtx(ctx)
+		if err != nil && err != redis.Nil {
+			ginx.AbortResponseWithCode(c, http.StatusInternalServerError, agerrors.NewCode(agcodes.CodeInternalError, err.Error()))
+			return
+		}
+		if !locked {
+			ginx.AbortResponseWithCode(c, http.StatusConflict, agerrors.NewCode(agcodes.WithCode(agcodes.CodeConflict, "request with the same Idempotency-Key is in progress")))
+			return
+		}
+		// the request context may be canceled once the response is written
+		defer lock.UnLockCtx(context.WithoutCancel(ctx))
+
+		// the first request may have finished between the check and the lock
+		if replayed := replayIdempotent(c, conf.Client, key, bodyHash); replayed {
+			return
+		}
+
+		before := c.Writer.Header().Clone()
+		crw := NewCachedResponseWriter(c.Writer)
+		c.Writer = crw
+		defer func() { c.Writer = crw.ResponseWriter }()
+		c.Next()
+
+		status := crw.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		record, err := json.Marshal(&idempotentRecord{
+			BodyHash: bodyHash,
+			Status:   status,
+			Header:   AddedHeader(before, crw.Header()),
+			Body:     crw.Cache.Bytes(),
+		})
+		if err != nil {
+			_ = c.Error(err)
+			return
+		}
+		if err := conf.Client.Set(context.WithoutCancel(ctx), key, record, ttl).Err(); err != nil {
+			_ = c.Error(err)
+		}
+	}
+}
+
+// replayIdempotent writes the stored response for key, it reports whether the request has been answered.
+func replayIdempotent(c *gin.Context, client redis.Cmdable, key, bodyHash string) bool {
+	data, err := client.Get(c.Request.Context(), key).Bytes()
+	if err != nil {
+		if err != redis.Nil {
+			_ = c.Error(err)
+		}
+		return false
+	}
+	record := &idempotentRecord{}
+	if err := json.Unmarshal(data, record); err != nil {
+		_ = c.Error(err)
+		return false
+	}
+	if record.BodyHash != bodyHash {
+		ginx.AbortResponseWithCode(c, http.StatusConflict, agerrors.NewCode(agcodes.WithCode(agcodes.CodeConflict, "Idempotency-Key has been used by a different request body")))
+		return true
+	}
+
+	for k, vs := range record.Header {
+		c.Writer.Header()[k] = vs
+	}
+	c.Writer.Header().Set(IdempotentReplayedHeader, "true")
+	c.Writer.WriteHeader(record.Status)
+	_, _ = c.Writer.Write(record.Body)
+	c.Abort()
+	return true
+}
@@ -0,0 +1,178 @@
+package ginMiddleWare
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis implements the commands used by Idempotency and RedisLock, it fails on canceled contexts as a client does.
+type fakeRedis struct {
+	redis.Cmdable
+
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{data: map[string]string{}}
+}
+
+func (f *fakeRedis) Get(ctx context.Context, key string) *redis.StringCmd {
+	if err := ctx.Err(); err != nil {
+		return redis.NewStringResult("", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.data[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(v, nil)
+}
+
+func (f *fakeRedis) Set(ctx context.Context, key string, value interface{}, _ time.Duration) *redis.StatusCmd {
+	if err := ctx.Err(); err != nil {
+		return redis.NewStatusResult("", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch v := value.(type) {
+	case []byte:
+		f.data[key] = string(v)
+	case string:
+		f.data[key] = v
+	}
+	return redis.NewStatusResult("OK", nil)
+}
+
+// Eval runs the lock and unlock scripts of RedisLock.
+func (f *fakeRedis) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd {
+	if err := ctx.Err(); err != nil {
+		return redis.NewCmdResult(nil, err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := keys[0]
+	if strings.Contains(script, "DEL") {
+		value := args[0].([]string)[0]
+		if f.data[key] != value {
+			return redis.NewCmdResult(int64(0), nil)
+		}
+		delete(f.data, key)
+		return redis.NewCmdResult(int64(1), nil)
+	}
+	value := args[0].(string)
+	if cur, ok := f.data[key]; ok && cur != value {
+		return redis.NewCmdResult(nil, redis.Nil)
+	}
+	f.data[key] = value
+	return redis.NewCmdResult("OK", nil)
+}
+
+func (f *fakeRedis) has(key string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.data[key]
+	return ok
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	client := newFakeRedis()
+	calls := 0
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Header("X-Request-Id", c.GetHeader("X-Request-Id"))
+		c.Next()
+	})
+	r.Use(Idempotency(&IdempotencyConfig{Client: client}))
+	r.POST("/orders", func(c *gin.Context) {
+		calls++
+		c.Header("Location", "/orders/1")
+		c.String(http.StatusCreated, "created %d", calls)
+	})
+	r.POST("/fail", func(c *gin.Context) {
+		calls++
+		c.String(http.StatusInternalServerError, "failed")
+	})
+	var cancelRequest context.CancelFunc
+	r.POST("/cancel", func(c *gin.Context) {
+		calls++
+		c.String(http.StatusOK, "done")
+		// the client went away once the response is written
+		cancelRequest()
+	})
+
+	do := func(path, key, body, reqID string) *httptest.ResponseRecorder {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		cancelRequest = cancel
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)).WithContext(ctx)
+		req.Header.Set(IdempotencyKeyHeader, key)
+		req.Header.Set("X-Request-Id", reqID)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("replay", func(t *testing.T) {
+		w := do("/orders", "k1", `{"n":1}`, "req-1")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "created 1", w.Body.String())
+		assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+
+		w = do("/orders", "k1", `{"n":1}`, "req-2")
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "created 1", w.Body.String())
+		assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, "/orders/1", w.Header().Get("Location"))
+		// headers set before the handler are not stored
+		assert.Equal(t, []string{"req-2"}, w.Header().Values("X-Request-Id"))
+		assert.Equal(t, 1, calls)
+		assert.False(t, client.has("idempotency:POST:/orders:k1:lock"))
+	})
+
+	t.Run("body mismatch", func(t *testing.T) {
+		w := do("/orders", "k1", `{"n":2}`, "req-3")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		client.mu.Lock()
+		client.data["idempotency:POST:/orders:k2:lock"] = "other"
+		client.mu.Unlock()
+		w := do("/orders", "k2", `{"n":1}`, "req-4")
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("5xx not stored", func(t *testing.T) {
+		w := do("/fail", "k3", `{}`, "req-5")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.False(t, client.has("idempotency:POST:/fail:k3"))
+		assert.False(t, client.has("idempotency:POST:/fail:k3:lock"))
+
+		w = do("/fail", "k3", `{}`, "req-6")
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("canceled request", func(t *testing.T) {
+		w := do("/cancel", "k4", `{}`, "req-7")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, client.has("idempotency:POST:/cancel:k4"))
+		assert.False(t, client.has("idempotency:POST:/cancel:k4:lock"))
+	})
+}
@@ -0,0 +1,159 @@
+package ginMiddleWare
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+	"github.com/kweaver-ai/idrm-go-frame/core/transport/rest/ginx"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RecoveryConfig config of Recovery
+type RecoveryConfig struct {
+	// Logger default zapx.DefaultLogger()
+	Logger zapx.Logger
+	// RequestIDHeader header carrying the request id, default X-Request-ID.
+	// The context value zapx.KeyRequestID is used when the header is empty.
+	RequestIDHeader string
+	// DisableStack omits the stack from the log
+	DisableStack bool
+	// OnPanic is called after the panic was logged, before the response is written
+	OnPanic func(c *gin.Context, err error)
+}
+
+// Recovery recovers panics of the handlers and responds 500 with agcodes.CodeInternalError.
+func Recovery() gin.HandlerFunc {
+	return RecoveryWithConfig(nil)
+}
+
+// RecoveryWithConfig recovers panics of the handlers. The panic becomes an agerrors error
+// carrying the stack, it is logged with the request and trace id, recorded on the active span
+// and answered with the standard HttpError body. Panics caused by a client that went away
+// (broken pipe, connection reset, http.ErrAbortHandler) only abort the request.
+func RecoveryWithConfig(conf *RecoveryConfig) gin.HandlerFunc {
+	if conf == nil {
+		conf = &RecoveryConfig{}
+	}
+	requestIDHeader := conf.RequestIDHeader
+	if requestIDHeader == "" {
+		requestIDHeader = "X-Request-ID"
+	}
+
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			err := agerrors.NewPanic(recovered)
+			logger := conf.Logger
+			if logger == nil {
+				logger = zapx.DefaultLogger()
+			}
+
+			ctx := c.Request.Context()
+			fields := []zapx.Field{
+				zapx.String("method", c.Request.Method),
+				zapx.String("path", c.Request.URL.Path),
+				zapx.String("route", c.FullPath()),
+				zapx.String("traceID", trace.SpanFromContext(ctx).SpanContext().TraceID().String()),
+			}
+			if requestID := requestID(c, requestIDHeader); requestID != "" {
+				fields = append(fields, zapx.String(zapx.KeyRequestID, requestID))
+			}
+
+			if isBrokenConnection(recovered) {
+				// the client is gone, there is nobody to answer
+				logger.Warn("[Recovery from broken connection]", append(fields, zapx.Any("error", recovered))...)
+				_ = c.Error(err)
+				c.Abort()
+				return
+			}
+
+			httpRequest, _ := httputil.DumpRequest(c.Request, false)
+			fields = append(fields,
+				zapx.String("error", err.Error()),
+				zapx.String("request", redactDumpedHeaders(string(httpRequest))),
+			)
+			if !conf.DisableStack {
+				fields = append(fields, zapx.String("stack", agerrors.Stack(err)))
+			}
+			logger.Error("[Recovery from panic]", fields...)
+
+			span := trace.SpanFromContext(ctx)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+
+			if conf.OnPanic != nil {
+				conf.OnPanic(c, err)
+			}
+			_ = c.Error(err)
+			if c.Writer.Written() {
+				// the response has started, the status can not be changed anymore
+				c.Abort()
+				return
+			}
+			ginx.AbortResponseWithCode(c, http.StatusInternalServerError, err)
+		}()
+
+		c.Next()
+	}
+}
+
+func requestID(c *gin.Context, header string) string {
+	if id := c.GetHeader(header); id != "" {
+		return id
+	}
+	if id, ok := c.Request.Context().Value(zapx.KeyRequestID).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// isBrokenConnection reports whether the panic was caused by a connection closed by the client.
+func isBrokenConnection(recovered interface{}) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+	if errors.Is(err, http.ErrAbortHandler) || errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	var ne *net.OpError
+	if errors.As(err, &ne) {
+		var se *os.SyscallError
+		if errors.As(ne.Err, &se) {
+			msg := strings.ToLower(se.Error())
+			return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+		}
+	}
+	return false
+}
+
+// redactDumpedHeaders masks the credentials of a dumped request.
+func redactDumpedHeaders(dump string) string {
+	lines := strings.Split(dump, "\r\n")
+	for i, line := range lines {
+		name, _, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		for _, h := range DefaultRedactHeaders {
+			if strings.EqualFold(strings.TrimSpace(name), h) {
+				lines[i] = name + ": " + RedactedValue
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\r\n")
+}
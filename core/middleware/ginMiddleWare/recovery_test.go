@@ -0,0 +1,50 @@
+package ginMiddleWare
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var entries []zapcore.Entry
+	logger := zapx.DefaultLogger().WithHook(func(e zapcore.Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+
+	r := gin.New()
+	r.Use(RecoveryWithConfig(&RecoveryConfig{Logger: logger}))
+	r.GET("/panic", func(c *gin.Context) { panic("boom") })
+	r.GET("/broken", func(c *gin.Context) {
+		panic(&net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)})
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set("X-Request-ID", "r1")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"Public.InternalError"`)
+	assert.NotContains(t, w.Body.String(), "boom")
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, zapcore.ErrorLevel, entries[0].Level)
+	}
+
+	entries = nil
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/broken", nil))
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, zapcore.WarnLevel, entries[0].Level)
+	}
+}
@@ -0,0 +1,157 @@
+package security
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultAllowMethods methods allowed by preflight responses by default.
+var DefaultAllowMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions}
+
+// ErrAnyOriginCredentials "*" in AllowOrigins with AllowCredentials would let every site send credentialed requests.
+var ErrAnyOriginCredentials = errors.New(`security: cors allow_origins "*" can not be used with allow_credentials`)
+
+// CORSConfig 跨域配置
+type CORSConfig struct {
+	Enabled bool `json:"enabled"`
+	// AllowOrigins "*", "https://app.example.com" or "https://*.example.com" matching every subdomain
+	AllowOrigins []string `json:"allow_origins"`
+	// AllowMethods default DefaultAllowMethods
+	AllowMethods []string `json:"allow_methods"`
+	// AllowHeaders default the headers asked by the preflight request
+	AllowHeaders  []string `json:"allow_headers"`
+	ExposeHeaders []string `json:"expose_headers"`
+	// AllowCredentials allows cookies, the request origin is answered, it can not be used with "*"
+	AllowCredentials bool `json:"allow_credentials"`
+	// MaxAge seconds browsers cache a preflight response, 0 leaves it to the browser
+	MaxAge int `json:"max_age"`
+}
+
+type cors struct {
+	enabled       bool
+	anyOrigin     bool
+	origins       map[string]bool
+	wildcards     [][2]string // scheme://, .domain
+	methods       string
+	headers       string
+	exposeHeaders string
+	credentials   bool
+	maxAge        string
+}
+
+// CORS returns the CORS middleware of conf, see Security for a reloadable one.
+// It panics when conf is invalid, e.g. ErrAnyOriginCredentials.
+func CORS(conf CORSConfig) gin.HandlerFunc {
+	conf.Enabled = true
+	cs, err := newCORS(conf)
+	if err != nil {
+		panic(err)
+	}
+	return func(c *gin.Context) {
+		if cs.handle(c) {
+			c.Next()
+		}
+	}
+}
+
+func newCORS(conf CORSConfig) (*cors, error) {
+	cs := &cors{
+		enabled:       conf.Enabled,
+		origins:       make(map[string]bool),
+		headers:       strings.Join(conf.AllowHeaders, ", "),
+		exposeHeaders: strings.Join(conf.ExposeHeaders, ", "),
+		credentials:   conf.AllowCredentials,
+	}
+	for _, o := range conf.AllowOrigins {
+		o = strings.ToLower(strings.TrimRight(strings.TrimSpace(o), "/"))
+		switch {
+		case o == "*":
+			cs.anyOrigin = true
+		case strings.Contains(o, "://*."):
+			idx := strings.Index(o, "://*.")
+			cs.wildcards = append(cs.wildcards, [2]string{o[:idx+3], o[idx+4:]})
+		case o != "":
+			cs.origins[o] = true
+		}
+	}
+	methods := conf.AllowMethods
+	if len(methods) == 0 {
+		methods = DefaultAllowMethods
+	}
+	cs.methods = strings.ToUpper(strings.Join(methods, ", "))
+	if conf.MaxAge > 0 {
+		cs.maxAge = strconv.Itoa(conf.MaxAge)
+	}
+	if cs.enabled && cs.anyOrigin && cs.credentials {
+		return nil, ErrAnyOriginCredentials
+	}
+	return cs, nil
+}
+
+func (cs *cors) allowed(origin string) bool {
+	if cs.anyOrigin {
+		return true
+	}
+	origin = strings.ToLower(origin)
+	if cs.origins[origin] {
+		return true
+	}
+	for _, w := range cs.wildcards {
+		if host := strings.TrimPrefix(origin, w[0]); host != origin && strings.HasSuffix(host, w[1]) && len(host) > len(w[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// handle writes the CORS headers, it answers preflight requests and reports whether to go on.
+func (cs *cors) handle(c *gin.Context) bool {
+	origin := c.GetHeader("Origin")
+	if !cs.enabled || origin == "" {
+		return true
+	}
+	h := c.Writer.Header()
+	h.Add("Vary", "Origin")
+	preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+	if !cs.allowed(origin) {
+		if preflight {
+			c.AbortWithStatus(http.StatusForbidden)
+			return false
+		}
+		// the browser blocks the response without the CORS headers
+		return true
+	}
+
+	if cs.anyOrigin {
+		h.Set("Access-Control-Allow-Origin", "*")
+	} else {
+		h.Set("Access-Control-Allow-Origin", origin)
+	}
+	if cs.credentials {
+		h.Set("Access-Control-Allow-Credentials", "true")
+	}
+	if !preflight {
+		if cs.exposeHeaders != "" {
+			h.Set("Access-Control-Expose-Headers", cs.exposeHeaders)
+		}
+		return true
+	}
+
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+	h.Set("Access-Control-Allow-Methods", cs.methods)
+	if cs.headers != "" {
+		h.Set("Access-Control-Allow-Headers", cs.headers)
+	} else if requested := c.GetHeader("Access-Control-Request-Headers"); requested != "" {
+		h.Set("Access-Control-Allow-Headers", requested)
+	}
+	if cs.maxAge != "" {
+		h.Set("Access-Control-Max-Age", cs.maxAge)
+	}
+	c.AbortWithStatus(http.StatusNoContent)
+	return false
+}
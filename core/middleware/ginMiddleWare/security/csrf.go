@@ -0,0 +1,138 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+	"github.com/kweaver-ai/idrm-go-frame/core/transport/rest/ginx"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultCSRFCookie cookie holding the token
+	DefaultCSRFCookie = "csrf_token"
+	// DefaultCSRFHeader header the client copies the cookie to
+	DefaultCSRFHeader = "X-CSRF-Token"
+	// DefaultCSRFFormField form field accepted instead of the header
+	DefaultCSRFFormField = "_csrf"
+	// CSRFTokenKey gin context key of the token of the request
+	CSRFTokenKey = "security.csrf_token"
+)
+
+// CSRFConfig double submit cookie 配置.
+// Safe requests get a random token cookie, unsafe ones must send it back in the header or the form field.
+type CSRFConfig struct {
+	Enabled      bool   `json:"enabled"`
+	CookieName   string `json:"cookie_name"`
+	HeaderName   string `json:"header_name"`
+	FormField    string `json:"form_field"`
+	CookiePath   string `json:"cookie_path"`
+	CookieDomain string `json:"cookie_domain"`
+	// CookieMaxAge seconds, 0 makes a session cookie
+	CookieMaxAge int `json:"cookie_max_age"`
+	// Secure default true on https requests
+	Secure bool `json:"secure"`
+	// SameSite lax, strict or none, default lax
+	SameSite string `json:"same_site"`
+	// Exempt route selectors not checked: "/api/v1/callback", "POST /api/v1/login", "/api/v1/hooks/*"
+	Exempt []string `json:"exempt"`
+}
+
+type csrf struct {
+	conf   CSRFConfig
+	exempt *routeSelector
+}
+
+// CSRF returns the CSRF middleware of conf, see Security for a reloadable one.
+func CSRF(conf CSRFConfig) gin.HandlerFunc {
+	conf.Enabled = true
+	cs := newCSRF(conf)
+	return func(c *gin.Context) {
+		if cs.handle(c) {
+			c.Next()
+		}
+	}
+}
+
+func newCSRF(conf CSRFConfig) *csrf {
+	conf.CookieName = orDefault(conf.CookieName, DefaultCSRFCookie)
+	conf.HeaderName = orDefault(conf.HeaderName, DefaultCSRFHeader)
+	conf.FormField = orDefault(conf.FormField, DefaultCSRFFormField)
+	conf.CookiePath = orDefault(conf.CookiePath, "/")
+	return &csrf{conf: conf, exempt: newRouteSelector(conf.Exempt)}
+}
+
+// CSRFToken returns the token of the request, to render it in a form.
+func CSRFToken(c *gin.Context) string {
+	return c.GetString(CSRFTokenKey)
+}
+
+func (cs *csrf) handle(c *gin.Context) bool {
+	if !cs.conf.Enabled {
+		return true
+	}
+	cookie, _ := c.Cookie(cs.conf.CookieName)
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		if cookie == "" {
+			cookie = newToken()
+			cs.setCookie(c, cookie)
+		}
+		c.Set(CSRFTokenKey, cookie)
+		return true
+	}
+	if cs.exempt.match(c) {
+		return true
+	}
+
+	sent := c.GetHeader(cs.conf.HeaderName)
+	if sent == "" && isForm(c) {
+		sent = c.PostForm(cs.conf.FormField)
+	}
+	if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(sent)) != 1 {
+		ginx.AbortResponseWithCode(c, http.StatusForbidden, agerrors.NewCode(agcodes.CodeCSRFTokenInvalid))
+		return false
+	}
+	c.Set(CSRFTokenKey, cookie)
+	return true
+}
+
+func (cs *csrf) setCookie(c *gin.Context, token string) {
+	sameSite := http.SameSiteLaxMode
+	switch strings.ToLower(cs.conf.SameSite) {
+	case "strict":
+		sameSite = http.SameSiteStrictMode
+	case "none":
+		sameSite = http.SameSiteNoneMode
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:   cs.conf.CookieName,
+		Value:  token,
+		Path:   cs.conf.CookiePath,
+		Domain: cs.conf.CookieDomain,
+		MaxAge: cs.conf.CookieMaxAge,
+		Secure: cs.conf.Secure || isHTTPS(c) || sameSite == http.SameSiteNoneMode,
+		// read by the page script to copy it into the header
+		HttpOnly: false,
+		SameSite: sameSite,
+	})
+}
+
+func isForm(c *gin.Context) bool {
+	ct := c.ContentType()
+	return ct == "application/x-www-form-urlencoded" || ct == "multipart/form-data"
+}
+
+func newToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,158 @@
+// Package security provides the CORS, security headers and CSRF middlewares of web facing services.
+package security
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config"
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultConfigKey config key of the security settings.
+const DefaultConfigKey = "security"
+
+// Config security 配置
+//
+//	security:
+//	  cors:
+//	    enabled: true
+//	    allow_origins: ["https://*.example.com"]
+//	    allow_credentials: true
+//	    max_age: 600
+//	  headers:
+//	    enabled: true
+//	    hsts_max_age: 31536000
+//	    content_security_policy: "default-src 'self'"
+//	  csrf:
+//	    enabled: true
+//	    exempt: ["POST /api/v1/callback/*"]
+type Config struct {
+	CORS    CORSConfig    `json:"cors"`
+	Headers HeadersConfig `json:"headers"`
+	CSRF    CSRFConfig    `json:"csrf"`
+}
+
+// Security the CORS, security headers and CSRF middlewares, in this order, sharing a config that can be replaced at runtime.
+type Security struct {
+	current atomic.Pointer[compiled]
+}
+
+type compiled struct {
+	cors    *cors
+	headers *headers
+	csrf    *csrf
+}
+
+// New creates the middlewares of conf.
+func New(conf Config) (*Security, error) {
+	s := &Security{}
+	if err := s.Update(conf); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Update replaces the config, requests already running keep the old one.
+// An invalid conf is returned as error and the current config is kept.
+func (s *Security) Update(conf Config) error {
+	cs, err := newCORS(conf.CORS)
+	if err != nil {
+		return err
+	}
+	s.current.Store(&compiled{
+		cors:    cs,
+		headers: newHeaders(conf.Headers),
+		csrf:    newCSRF(conf.CSRF),
+	})
+	return nil
+}
+
+// Handler returns the middleware, use it before the routes:
+//
+//	engine.Use(s.Handler())
+func (s *Security) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cur := s.current.Load()
+		if cur.cors.handle(c) && cur.headers.handle(c) && cur.csrf.handle(c) {
+			c.Next()
+		}
+	}
+}
+
+// FromConfig creates the middlewares of key in conf and reloads them when key changes, see config.Watch.
+// conf nil uses the config installed by config.Init. Invalid changes are logged and ignored.
+func FromConfig(conf config.Config, key string) (*Security, error) {
+	if key == "" {
+		key = DefaultConfigKey
+	}
+	value, watch := config.GetValue, config.Watch
+	if conf != nil {
+		value, watch = conf.Value, conf.Watch
+	}
+
+	var sc Config
+	if err := value(key).Scan(&sc); err != nil {
+		return nil, err
+	}
+	s, err := New(sc)
+	if err != nil {
+		return nil, err
+	}
+	err = watch(key, func(_ string, v config.Value) {
+		var next Config
+		if err := v.Scan(&next); err != nil {
+			zapx.Errorf("security: ignore invalid config %s: %v", key, err)
+			return
+		}
+		if err := s.Update(next); err != nil {
+			zapx.Errorf("security: ignore invalid config %s: %v", key, err)
+			return
+		}
+		zapx.Infof("security: config %s reloaded", key)
+	})
+	return s, err
+}
+
+// routeSelector matches "[METHOD ]path" selectors, a path ending with "*" matches its prefix.
+// Paths are matched against the route template and the request path.
+type routeSelector struct {
+	exact  map[string]bool
+	prefix []string
+}
+
+func newRouteSelector(selectors []string) *routeSelector {
+	rs := &routeSelector{exact: make(map[string]bool)}
+	for _, s := range selectors {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if strings.HasSuffix(s, "*") {
+			rs.prefix = append(rs.prefix, strings.TrimSuffix(s, "*"))
+		} else {
+			rs.exact[s] = true
+		}
+	}
+	return rs
+}
+
+func (rs *routeSelector) match(c *gin.Context) bool {
+	method := c.Request.Method
+	for _, path := range []string{c.FullPath(), c.Request.URL.Path} {
+		if path == "" {
+			continue
+		}
+		if rs.exact[path] || rs.exact[method+" "+path] {
+			return true
+		}
+		for _, p := range rs.prefix {
+			if strings.HasPrefix(path, p) || strings.HasPrefix(method+" "+path, p) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,236 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/config"
+	"github.com/kweaver-ai/idrm-go-frame/core/config/sources"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newEngine(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(handler)
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	r.GET("/api/v1/views", ok)
+	r.POST("/api/v1/views", ok)
+	r.POST("/api/v1/hooks/:name", ok)
+	r.POST("/api/v1/login", ok)
+	return r
+}
+
+func serve(r *gin.Engine, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORS(t *testing.T) {
+	r := newEngine(CORS(CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com", "https://*.example.org"},
+		AllowCredentials: true,
+		ExposeHeaders:    []string{"X-Request-ID"},
+		MaxAge:           600,
+	}))
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/api/v1/views", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "Content-Type, X-CSRF-Token")
+		return serve(r, req)
+	}
+
+	w := preflight("https://a.b.example.org")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://a.b.example.org", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	assert.Equal(t, "Content-Type, X-CSRF-Token", w.Header().Get("Access-Control-Allow-Headers"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), "PATCH")
+
+	assert.Equal(t, http.StatusForbidden, preflight("https://example.org").Code)
+	assert.Equal(t, http.StatusForbidden, preflight("http://a.example.org").Code)
+	assert.Equal(t, http.StatusForbidden, preflight("https://evil.com").Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/views", nil)
+	req.Header.Set("Origin", "https://APP.example.com")
+	w = serve(r, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "https://APP.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "X-Request-ID", w.Header().Get("Access-Control-Expose-Headers"))
+	assert.Equal(t, "Origin", w.Header().Get("Vary"))
+
+	req.Header.Set("Origin", "https://evil.com")
+	w = serve(r, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSAnyOrigin(t *testing.T) {
+	r := newEngine(CORS(CORSConfig{AllowOrigins: []string{"*"}}))
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/views", nil)
+	req.Header.Set("Origin", "https://anywhere.io")
+	assert.Equal(t, "*", serve(r, req).Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSAnyOriginCredentials(t *testing.T) {
+	conf := CORSConfig{Enabled: true, AllowOrigins: []string{"*"}, AllowCredentials: true}
+	_, err := New(Config{CORS: conf})
+	assert.ErrorIs(t, err, ErrAnyOriginCredentials)
+	assert.PanicsWithError(t, ErrAnyOriginCredentials.Error(), func() { CORS(conf) })
+
+	s, err := New(Config{CORS: CORSConfig{Enabled: true, AllowOrigins: []string{"https://app.example.com"}, AllowCredentials: true}})
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.Update(Config{CORS: conf}), ErrAnyOriginCredentials)
+
+	// the previous config is kept
+	r := newEngine(s.Handler())
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/views", nil)
+	req.Header.Set("Origin", "https://evil.com")
+	w := serve(r, req)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
+
+func TestHeaders(t *testing.T) {
+	r := newEngine(Headers(HeadersConfig{
+		HSTSMaxAge:            31536000,
+		HSTSIncludeSubdomains: true,
+		ContentSecurityPolicy: "default-src 'self'",
+		ReferrerPolicy:        "-",
+	}))
+
+	w := serve(r, httptest.NewRequest(http.MethodGet, "/api/v1/views", nil))
+	assert.Equal(t, "default-src 'self'", w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Empty(t, w.Header().Get("Referrer-Policy"))
+	assert.Empty(t, w.Header().Get("Strict-Transport-Security"), "plain http")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/views", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	assert.Equal(t, "max-age=31536000; includeSubDomains", serve(r, req).Header().Get("Strict-Transport-Security"))
+}
+
+func TestCSRF(t *testing.T) {
+	r := newEngine(CSRF(CSRFConfig{Exempt: []string{"/api/v1/hooks/*", "POST /api/v1/login"}}))
+
+	w := serve(r, httptest.NewRequest(http.MethodGet, "/api/v1/views", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	cookie := cookies[0]
+	assert.Equal(t, DefaultCSRFCookie, cookie.Name)
+	assert.Equal(t, http.SameSiteLaxMode, cookie.SameSite)
+
+	post := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/views", nil)
+		req.AddCookie(cookie)
+		if token != "" {
+			req.Header.Set(DefaultCSRFHeader, token)
+		}
+		return serve(r, req)
+	}
+	assert.Equal(t, http.StatusOK, post(cookie.Value).Code)
+	w = post("")
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Public.CSRFTokenInvalid")
+	assert.Equal(t, http.StatusForbidden, post("forged").Code)
+
+	form := url.Values{DefaultCSRFFormField: {cookie.Value}}
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/views", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(cookie)
+	assert.Equal(t, http.StatusOK, serve(r, req).Code)
+
+	// exempt routes
+	assert.Equal(t, http.StatusOK, serve(r, httptest.NewRequest(http.MethodPost, "/api/v1/hooks/build", nil)).Code)
+	assert.Equal(t, http.StatusOK, serve(r, httptest.NewRequest(http.MethodPost, "/api/v1/login", nil)).Code)
+}
+
+// memSource a config source whose content is replaced by update.
+type memSource struct {
+	data    chan []byte
+	current []byte
+}
+
+func (s *memSource) Load() ([]*sources.KeyValue, error) {
+	return []*sources.KeyValue{{Key: "config.yaml", Value: s.current, Format: "yaml"}}, nil
+}
+
+func (s *memSource) Watch() (sources.Watcher, error) { return s, nil }
+
+func (s *memSource) Next() ([]*sources.KeyValue, error) {
+	data, ok := <-s.data
+	if !ok {
+		return nil, context.Canceled
+	}
+	s.current = data
+	return s.Load()
+}
+
+func (s *memSource) Stop() error {
+	close(s.data)
+	return nil
+}
+
+func TestFromConfig(t *testing.T) {
+	src := &memSource{data: make(chan []byte), current: []byte(`
+security:
+  headers:
+    enabled: true
+    frame_options: SAMEORIGIN
+`)}
+	conf := config.New(config.WithSource(src))
+	require.NoError(t, conf.Load())
+	defer conf.Close()
+
+	s, err := FromConfig(conf, "")
+	require.NoError(t, err)
+	r := newEngine(s.Handler())
+	get := func() *httptest.ResponseRecorder {
+		return serve(r, httptest.NewRequest(http.MethodGet, "/api/v1/views", nil))
+	}
+	assert.Equal(t, "SAMEORIGIN", get().Header().Get("X-Frame-Options"))
+	assert.Empty(t, get().Result().Cookies())
+
+	src.data <- []byte(`
+security:
+  headers:
+    enabled: true
+    frame_options: DENY
+  csrf:
+    enabled: true
+`)
+	assert.Eventually(t, func() bool {
+		w := get()
+		return w.Header().Get("X-Frame-Options") == "DENY" && len(w.Result().Cookies()) == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestFromConfigAnyOriginCredentials(t *testing.T) {
+	src := &memSource{data: make(chan []byte), current: []byte(`
+security:
+  cors:
+    enabled: true
+    allow_origins: ["*"]
+    allow_credentials: true
+`)}
+	conf := config.New(config.WithSource(src))
+	require.NoError(t, conf.Load())
+	defer conf.Close()
+
+	_, err := FromConfig(conf, "")
+	assert.ErrorIs(t, err, ErrAnyOriginCredentials)
+}
@@ -1,45 +1,84 @@
 package ginMiddleWare
 
 import (
-    "context"
-    "fmt"
-    "github.com/gin-gonic/gin"
-    "log"
-    "net/http"
-    "time"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+	"github.com/kweaver-ai/idrm-go-frame/core/transport/rest/ginx"
+
+	"github.com/gin-gonic/gin"
 )
 
+// TimeoutConfig is config setting for Timeout.
+type TimeoutConfig struct {
+	// Default timeout of every route, <= 0 means no timeout
+	Default time.Duration
+	// Routes per route timeout, keyed by route template ("/api/v1/users/:id")
+	// or method and route template ("POST /api/v1/users"), the latter wins.
+	Routes map[string]time.Duration
+	// StatusCode of the timeout response, default 504
+	StatusCode int
+}
+
+func (conf *TimeoutConfig) timeout(c *gin.Context) time.Duration {
+	if d, ok := conf.Routes[c.Request.Method+" "+c.FullPath()]; ok {
+		return d
+	}
+	if d, ok := conf.Routes[c.FullPath()]; ok {
+		return d
+	}
+	return conf.Default
+}
+
+// TimeOut returns a Timeout middleware with the same duration for every route.
 func TimeOut(d time.Duration) gin.HandlerFunc {
+	return Timeout(&TimeoutConfig{Default: d})
+}
+
+// Timeout bounds the request context with a deadline.
+//
+// The handler runs on the request goroutine with c.Request.Context() carrying
+// the deadline, so database, redis and http calls made with that context are
+// cancelled when it expires. The response is buffered: if the deadline passed
+// by the time the handler returns, whatever it wrote is dropped and a
+// CodeRequestTimeout error is sent instead, so a timeout reply and a late
+// handler write never interleave. Handlers that ignore the context are not
+// interrupted, and streaming responses are not supported behind this middleware.
+func Timeout(conf *TimeoutConfig) gin.HandlerFunc {
+	status := conf.StatusCode
+	if status == 0 {
+		status = http.StatusGatewayTimeout
+	}
+
+	return func(c *gin.Context) {
+		d := conf.timeout(c)
+		if d <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		bw := NewBufferedResponseWriter(c.Writer)
+		func() {
+			// a panicking handler leaves the response to Recovery on the real writer
+			c.Writer = bw
+			defer func() { c.Writer = bw.ResponseWriter }()
+			c.Next()
+		}()
 
-    return func(c *gin.Context) {
-
-        finish := make(chan struct{}, 1)
-        panicChan := make(chan interface{}, 1)
-        // 执行业务逻辑前预操作：初始化超时context
-        durationCtx, cancel := context.WithTimeout(c.Request.Context(), d)
-        defer cancel()
-
-        go func() {
-            defer func() {
-                if p := recover(); p != nil {
-                    panicChan <- p
-                }
-            }()
-            // 使用next执行具体的业务逻辑
-            c.Next()
-
-            finish <- struct{}{}
-        }()
-
-        select{
-
-        case p := <-panicChan:
-            c.JSON(http.StatusInternalServerError,gin.H{})
-            log.Println(p)
-        case <-finish:
-            fmt.Println("finish")
-         case <-durationCtx.Done():
-             c.JSON(http.StatusBadGateway,gin.H{})
-        }
-    }
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			ginx.AbortResponseWithCode(c, status, agerrors.NewCode(agcodes.WithCode(agcodes.CodeRequestTimeout, d.String())))
+			return
+		}
+		if err := bw.Commit(); err != nil {
+			_ = c.Error(err)
+		}
+	}
 }
@@ -0,0 +1,68 @@
+package ginMiddleWare
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Timeout(&TimeoutConfig{
+		Default: 20 * time.Millisecond,
+		Routes:  map[string]time.Duration{"GET /slow-allowed": time.Second},
+	}))
+	slow := func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+		case <-time.After(200 * time.Millisecond):
+		}
+		c.Header("X-Late", "1")
+		c.String(http.StatusOK, "late")
+	}
+	r.GET("/fast", func(c *gin.Context) {
+		c.Header("X-Handler", "1")
+		c.String(http.StatusCreated, "ok")
+	})
+	r.GET("/slow", slow)
+	r.GET("/slow-allowed", slow)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+	assert.Equal(t, "1", w.Header().Get("X-Handler"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), "Public.RequestTimeout")
+	assert.NotContains(t, w.Body.String(), "late")
+	assert.Empty(t, w.Header().Get("X-Late"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow-allowed", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "late", w.Body.String())
+}
+
+func TestTimeoutPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Recovery(), TimeOut(time.Second))
+	r.GET("/panic", func(c *gin.Context) {
+		c.Header("X-Handler", "1")
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"Public.InternalError"`)
+	assert.Empty(t, w.Header().Get("X-Handler"))
+}
@@ -1,38 +1,25 @@
 package ginMiddleWare
 
 import (
+    "github.com/kweaver-ai/idrm-go-frame/core/i18n"
+
     "github.com/gin-gonic/gin"
-    "github.com/gin-gonic/gin/binding"
-    "github.com/go-playground/locales/en"
-    "github.com/go-playground/locales/zh"
-    "github.com/go-playground/locales/zh_Hant_TW"
-    ut "github.com/go-playground/universal-translator"
-    validator "github.com/go-playground/validator/v10"
-    en_translations "github.com/go-playground/validator/v10/translations/en"
-    zh_translations "github.com/go-playground/validator/v10/translations/zh"
 )
 
+// Translations selects the request locale, see i18n.Middleware.
+// The legacy "locale" header is still honored when Accept-Language is missing.
+// Validator translators are built once by validator.SetupValidator, get them with
+// service.GetTranslator(c) instead of c.Get("trans").
+//
+// Deprecated: use i18n.Middleware.
 func Translations() gin.HandlerFunc {
+    selectLocale := i18n.Middleware(nil)
     return func(c *gin.Context) {
-        uni := ut.New(en.New(), zh.New(), zh_Hant_TW.New())
-        locale := c.GetHeader("locale")
-        trans, _ := uni.GetTranslator(locale)
-        v, ok := binding.Validator.Engine().(*validator.Validate)
-        if ok {
-            switch locale {
-            case "zh":
-                _ = zh_translations.RegisterDefaultTranslations(v, trans)
-                break
-            case "en":
-                _ = en_translations.RegisterDefaultTranslations(v, trans)
-                break
-            default:
-                _ = zh_translations.RegisterDefaultTranslations(v, trans)
-                break
+        if c.GetHeader("Accept-Language") == "" {
+            if locale := c.GetHeader("locale"); locale != "" {
+                c.Request.Header.Set("Accept-Language", locale)
             }
-            c.Set("trans", trans)
         }
-
-        c.Next()
+        selectLocale(c)
     }
 }
@@ -1,6 +1,9 @@
 package ginMiddleWare
 
 import (
+    "github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+    "github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+    "github.com/kweaver-ai/idrm-go-frame/core/transport/rest/ginx"
     "github.com/gin-gonic/gin"
     "github.com/zeromicro/go-zero/core/logx"
     "go.opentelemetry.io/otel/trace"
@@ -22,6 +25,10 @@ type Config struct {
 
     // If true, log without request/response body.
     DisableBody bool
+    // Body policy of request/response body, query and header logging.
+    Body *BodyPolicy
+    // RouteBody per route body policy, keyed by route template, overrides Body.
+    RouteBody map[string]*BodyPolicy
 }
 
 
@@ -45,18 +52,28 @@ func GinZapWithConfig(w logx.Writer ,conf *Config) gin.HandlerFunc {
         skipPaths[path] = true
     }
 
+    defaultPolicy := newBodyLogPolicy(conf.Body, conf.DisableBody)
+    routePolicies := make(map[string]*bodyLogPolicy, len(conf.RouteBody))
+    for route, p := range conf.RouteBody {
+        routePolicies[route] = newBodyLogPolicy(p, conf.DisableBody)
+    }
 
     return func(c *gin.Context){
 
             start := time.Now()
 
+            policy := defaultPolicy
+            if p, ok := routePolicies[c.FullPath()]; ok {
+                policy = p
+            }
+
             // To record request body
-            var crc *cachedReadCloser
+            var crc *limitedReadCloser
             // To record response body
-            var crw *cachedResponseWriter
-            if !conf.DisableBody {
-                crc = NewCachedReadCloser(c.Request.Body)
-                crw = NewCachedResponseWriter(c.Writer)
+            var crw *limitedResponseWriter
+            if !policy.disable {
+                crc = newLimitedReadCloser(c.Request.Body, policy.maxBytes)
+                crw = newLimitedResponseWriter(c.Writer, policy.maxBytes)
 
                 c.Request.Body = crc
                 c.Writer = crw
@@ -85,7 +102,7 @@ func GinZapWithConfig(w logx.Writer ,conf *Config) gin.HandlerFunc {
                     logx.Field("status",c.Writer.Status()),
                     logx.Field("method", c.Request.Method),
                     logx.Field("path", path),
-                    logx.Field("query", query),
+                    logx.Field("query", policy.query(query)),
                     logx.Field("ip", c.ClientIP()),
                     logx.Field("user-agent", c.Request.UserAgent()),
                     logx.Field("latency", latency),
@@ -97,8 +114,14 @@ func GinZapWithConfig(w logx.Writer ,conf *Config) gin.HandlerFunc {
                 if conf.TraceID {
                     fields = append(fields, logx.Field("traceID", trace.SpanFromContext(c.Request.Context()).SpanContext().TraceID().String()))
                 }
-                if !conf.DisableBody {
-                    fields = append(fields, logx.Field("request-body", &crc.Cache), logx.Field("response-body", &crw.Cache))
+                if policy.logHeaders {
+                    fields = append(fields, logx.Field("request-header", policy.headers(c.Request.Header)))
+                }
+                if !policy.disable {
+                    fields = append(fields,
+                        logx.Field("request-body", policy.body(c.Request.Header.Get("Content-Type"), crc.cache)),
+                        logx.Field("response-body", policy.body(c.Writer.Header().Get("Content-Type"), crw.cache)),
+                    )
                 }
                 w.Info(path, fields...)
              }
@@ -150,7 +173,7 @@ func RecoveryWithZap(w logx.Writer,stack bool) gin.HandlerFunc {
                         logx.Field("request", string(httpRequest)),
                     )
                 }
-                c.AbortWithStatus(http.StatusInternalServerError)
+                ginx.AbortResponseWithCode(c, http.StatusInternalServerError, agerrors.NewCode(agcodes.CodeInternalError))
             }
 
         }()
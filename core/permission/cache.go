@@ -0,0 +1,128 @@
+package permission
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/syncx"
+)
+
+// DefaultMaxCacheEntries decisions kept by a CachedAuthorizer, the least recently used are evicted first.
+const DefaultMaxCacheEntries = 10000
+
+type cacheEntry struct {
+	key      string
+	allow    bool
+	expireAt time.Time
+}
+
+// CacheOption is CachedAuthorizer option.
+type CacheOption func(*CachedAuthorizer)
+
+// WithMaxEntries sets the number of decisions kept, DefaultMaxCacheEntries by default.
+func WithMaxEntries(n int) CacheOption {
+	return func(a *CachedAuthorizer) {
+		if n > 0 {
+			a.maxEntries = n
+		}
+	}
+}
+
+// CachedAuthorizer caches the decisions of another Authorizer in an LRU.
+// Errors are never cached, concurrent checks for the same key share one call.
+type CachedAuthorizer struct {
+	next       Authorizer
+	ttl        time.Duration
+	maxEntries int
+	sf         syncx.SingleFlight
+
+	mu      sync.Mutex
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+var _ Authorizer = (*CachedAuthorizer)(nil)
+
+// NewCachedAuthorizer wraps next with a ttl cache.
+func NewCachedAuthorizer(next Authorizer, ttl time.Duration, opts ...CacheOption) *CachedAuthorizer {
+	a := &CachedAuthorizer{
+		next:       next,
+		ttl:        ttl,
+		maxEntries: DefaultMaxCacheEntries,
+		sf:         syncx.NewSingleFlight(),
+		ll:         list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
+}
+
+func (a *CachedAuthorizer) Authorize(ctx context.Context, req *Request) (bool, error) {
+	key := req.cacheKey()
+	if allow, ok := a.load(key); ok {
+		return allow, nil
+	}
+
+	v, err := a.sf.Do(key, func() (interface{}, error) {
+		allow, err := a.next.Authorize(ctx, req)
+		if err != nil {
+			return false, err
+		}
+		a.store(key, allow)
+		return allow, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
+func (a *CachedAuthorizer) load(key string) (bool, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	el, ok := a.entries[key]
+	if !ok {
+		return false, false
+	}
+	e := el.Value.(*cacheEntry)
+	if !time.Now().Before(e.expireAt) {
+		a.ll.Remove(el)
+		delete(a.entries, key)
+		return false, false
+	}
+	a.ll.MoveToFront(el)
+	return e.allow, true
+}
+
+func (a *CachedAuthorizer) store(key string, allow bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if el, ok := a.entries[key]; ok {
+		a.ll.Remove(el)
+	}
+	a.entries[key] = a.ll.PushFront(&cacheEntry{key: key, allow: allow, expireAt: time.Now().Add(a.ttl)})
+	for a.ll.Len() > a.maxEntries {
+		el := a.ll.Back()
+		a.ll.Remove(el)
+		delete(a.entries, el.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of cached decisions, expired ones included until they are touched.
+func (a *CachedAuthorizer) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.ll.Len()
+}
+
+// Purge drops all cached decisions, e.g. after the policy changed.
+func (a *CachedAuthorizer) Purge() {
+	a.mu.Lock()
+	a.ll.Init()
+	a.entries = make(map[string]*list.Element)
+	a.mu.Unlock()
+}
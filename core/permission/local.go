@@ -0,0 +1,110 @@
+package permission
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/encoding"
+	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/json"
+	_ "github.com/kweaver-ai/idrm-go-frame/core/encoding/yaml"
+)
+
+// Policy is the content of a local policy file.
+//
+//	roles:
+//	  admin:
+//	    - resource: "*"
+//	      action: "*"
+//	  viewer:
+//	    - resource: 管理逻辑视图
+//	      action: 读取
+//	users:
+//	  u1:
+//	    - resource: 管理数据质量
+//	      action: 管理
+type Policy struct {
+	Roles map[string][]Grant `json:"roles" yaml:"roles"`
+	Users map[string][]Grant `json:"users" yaml:"users"`
+}
+
+// Grant resource/action pair, "*" matches anything.
+type Grant struct {
+	Resource string `json:"resource" yaml:"resource"`
+	Action   string `json:"action"   yaml:"action"`
+}
+
+func (g Grant) match(resource, action string) bool {
+	return (g.Resource == "*" || g.Resource == resource) && (g.Action == "*" || g.Action == action)
+}
+
+// LocalAuthorizer authorizes against a policy file.
+type LocalAuthorizer struct {
+	path string
+
+	mu     sync.RWMutex
+	policy *Policy
+}
+
+var _ Authorizer = (*LocalAuthorizer)(nil)
+
+// NewLocalAuthorizer loads a json or yaml policy file.
+func NewLocalAuthorizer(path string) (*LocalAuthorizer, error) {
+	a := &LocalAuthorizer{path: path}
+	if err := a.Reload(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// NewPolicyAuthorizer authorizes against an in-memory policy.
+func NewPolicyAuthorizer(p *Policy) *LocalAuthorizer {
+	return &LocalAuthorizer{policy: p}
+}
+
+// Reload reads the policy file again.
+func (a *LocalAuthorizer) Reload() error {
+	data, err := os.ReadFile(a.path)
+	if err != nil {
+		return err
+	}
+	ext := strings.TrimPrefix(filepath.Ext(a.path), ".")
+	if ext == "yml" {
+		ext = "yaml"
+	}
+	codec := encoding.GetCodec(ext)
+	if codec == nil {
+		return fmt.Errorf("unsupported policy file format: %s", ext)
+	}
+	p := &Policy{}
+	if err := codec.Unmarshal(data, p); err != nil {
+		return fmt.Errorf("parse policy file %s: %w", a.path, err)
+	}
+	a.mu.Lock()
+	a.policy = p
+	a.mu.Unlock()
+	return nil
+}
+
+func (a *LocalAuthorizer) Authorize(_ context.Context, req *Request) (bool, error) {
+	a.mu.RLock()
+	p := a.policy
+	a.mu.RUnlock()
+
+	for _, g := range p.Users[req.Subject.UserID] {
+		if g.match(req.Resource, req.Action) {
+			return true, nil
+		}
+	}
+	for _, role := range req.Subject.Roles {
+		for _, g := range p.Roles[role] {
+			if g.match(req.Resource, req.Action) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,102 @@
+package permission
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/auth"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+	"github.com/kweaver-ai/idrm-go-frame/core/transport/rest/ginx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SubjectFunc resolves the subject of the request.
+type SubjectFunc func(c *gin.Context) (Subject, bool)
+
+// MiddlewareOption is a permission middleware option.
+type MiddlewareOption func(*middlewareOptions)
+
+type middlewareOptions struct {
+	subject      SubjectFunc
+	denyUnmapped bool
+}
+
+// WithSubject overrides how the subject is resolved, by default it comes from the auth claims.
+func WithSubject(fn SubjectFunc) MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.subject = fn
+	}
+}
+
+// DenyUnmapped denies routes without any @Permission annotation instead of letting them through.
+func DenyUnmapped() MiddlewareOption {
+	return func(o *middlewareOptions) {
+		o.denyUnmapped = true
+	}
+}
+
+// Middleware enforces the generated menu resources.
+// The route template (c.FullPath) and method select the MenuResources of the
+// handler, the request passes when the subject is granted any of them.
+func Middleware(rs []*MenuResource, az Authorizer, opts ...MiddlewareOption) gin.HandlerFunc {
+	o := &middlewareOptions{subject: claimsSubject}
+	for _, opt := range opts {
+		opt(o)
+	}
+	table := newRouteTable(rs)
+
+	return func(c *gin.Context) {
+		resources := table.lookup(c.Request.Method, c.FullPath())
+		if len(resources) == 0 {
+			if o.denyUnmapped {
+				ginx.AbortResponseWithCode(c, http.StatusForbidden, agerrors.NewCode(agcodes.AuthorizationFailure))
+				return
+			}
+			c.Next()
+			return
+		}
+
+		subject, ok := o.subject(c)
+		if !ok {
+			ginx.AbortResponseWithCode(c, http.StatusUnauthorized, agerrors.NewCode(agcodes.NotAuthentication))
+			return
+		}
+
+		for _, r := range resources {
+			allow, err := az.Authorize(c.Request.Context(), &Request{
+				Subject:  subject,
+				Service:  r.ServiceName,
+				Resource: r.Resource,
+				Action:   r.Action,
+			})
+			if err != nil {
+				ginx.AbortResponseWithCode(c, http.StatusServiceUnavailable, agerrors.NewCode(agcodes.WithCode(agcodes.HydraException, err.Error())))
+				return
+			}
+			if allow {
+				c.Next()
+				return
+			}
+		}
+
+		ginx.AbortResponseWithCode(c, http.StatusForbidden, agerrors.NewCode(agcodes.WithCode(agcodes.AuthorizationFailure, deniedDetail(resources))))
+	}
+}
+
+func claimsSubject(c *gin.Context) (Subject, bool) {
+	claims, ok := auth.FromContext(c)
+	if !ok || claims == nil {
+		return Subject{}, false
+	}
+	return Subject{UserID: claims.UserID, Roles: claims.Roles}, true
+}
+
+func deniedDetail(rs []*MenuResource) []string {
+	detail := make([]string, 0, len(rs))
+	for _, r := range rs {
+		detail = append(detail, fmt.Sprintf("%s:%s", r.Resource, r.Action))
+	}
+	return detail
+}
@@ -0,0 +1,65 @@
+package permission
+
+import (
+	"context"
+	"strings"
+)
+
+// MenuResource route to (resource, action) relation generated by cmd/permission.
+type MenuResource struct {
+	ServiceName string `json:"service_name"`
+	Path        string `json:"path"`
+	Method      string `json:"method"`
+	Action      string `json:"action"`
+	Resource    string `json:"resource"`
+}
+
+// Subject who is accessing.
+type Subject struct {
+	UserID string   `json:"user_id"`
+	Roles  []string `json:"roles,omitempty"`
+}
+
+// Request a single authorization question: may subject do action on resource.
+type Request struct {
+	Subject  Subject `json:"subject"`
+	Service  string  `json:"service_name"`
+	Resource string  `json:"resource"`
+	Action   string  `json:"action"`
+}
+
+func (r *Request) cacheKey() string {
+	return strings.Join([]string{r.Subject.UserID, strings.Join(r.Subject.Roles, ","), r.Service, r.Resource, r.Action}, "\x00")
+}
+
+// Authorizer decides whether a request is allowed.
+type Authorizer interface {
+	Authorize(ctx context.Context, req *Request) (bool, error)
+}
+
+// AuthorizerFunc adapts a function to Authorizer.
+type AuthorizerFunc func(ctx context.Context, req *Request) (bool, error)
+
+func (f AuthorizerFunc) Authorize(ctx context.Context, req *Request) (bool, error) {
+	return f(ctx, req)
+}
+
+// routeTable indexes menu resources by method and gin route template.
+type routeTable map[string][]*MenuResource
+
+func newRouteTable(rs []*MenuResource) routeTable {
+	t := make(routeTable, len(rs))
+	for _, r := range rs {
+		key := routeKey(r.Method, r.Path)
+		t[key] = append(t[key], r)
+	}
+	return t
+}
+
+func (t routeTable) lookup(method, fullPath string) []*MenuResource {
+	return t[routeKey(method, fullPath)]
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
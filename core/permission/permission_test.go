@@ -0,0 +1,123 @@
+package permission
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testResources = []*MenuResource{
+	{ServiceName: "data-view", Path: "/api/data-view/v1/form_view", Method: "GET", Resource: "管理逻辑视图", Action: "读取"},
+	{ServiceName: "data-view", Path: "/api/data-view/v1/form_view/:id", Method: "POST", Resource: "管理逻辑视图", Action: "管理"},
+	{ServiceName: "data-view", Path: "/api/data-view/v1/form_view/:id", Method: "POST", Resource: "管理数据质量", Action: "管理"},
+}
+
+const testPolicy = `
+roles:
+  viewer:
+    - resource: 管理逻辑视图
+      action: 读取
+users:
+  u2:
+    - resource: 管理数据质量
+      action: "*"
+`
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testPolicy), 0o600))
+	local, err := NewLocalAuthorizer(path)
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if uid := c.GetHeader("X-User"); uid != "" {
+			c.Set("uid", uid)
+		}
+	})
+	r.Use(Middleware(testResources, local, WithSubject(func(c *gin.Context) (Subject, bool) {
+		uid := c.GetString("uid")
+		return Subject{UserID: uid, Roles: []string{c.GetHeader("X-Role")}}, uid != ""
+	})))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/data-view/v1/form_view", ok)
+	r.POST("/api/data-view/v1/form_view/:id", ok)
+	r.GET("/health", ok)
+
+	cases := []struct {
+		method, path, user, role string
+		code                     int
+	}{
+		{"GET", "/health", "", "", http.StatusOK},
+		{"GET", "/api/data-view/v1/form_view", "", "", http.StatusUnauthorized},
+		{"GET", "/api/data-view/v1/form_view", "u1", "viewer", http.StatusOK},
+		{"POST", "/api/data-view/v1/form_view/1", "u1", "viewer", http.StatusForbidden},
+		{"POST", "/api/data-view/v1/form_view/1", "u2", "", http.StatusOK},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, tc.path, nil)
+		req.Header.Set("X-User", tc.user)
+		req.Header.Set("X-Role", tc.role)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, tc.code, w.Code, "%s %s as %s", tc.method, tc.path, tc.user)
+		if tc.code == http.StatusForbidden {
+			assert.Contains(t, w.Body.String(), "Public.AuthorizationFailure")
+		}
+	}
+}
+
+func TestCachedAuthorizer(t *testing.T) {
+	var calls int32
+	next := AuthorizerFunc(func(ctx context.Context, req *Request) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		return req.Subject.UserID == "u1", nil
+	})
+	a := NewCachedAuthorizer(next, 50*time.Millisecond)
+	req := &Request{Subject: Subject{UserID: "u1"}, Resource: "r", Action: "a"}
+
+	for i := 0; i < 3; i++ {
+		allow, err := a.Authorize(context.Background(), req)
+		require.NoError(t, err)
+		assert.True(t, allow)
+	}
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+	time.Sleep(60 * time.Millisecond)
+	_, _ = a.Authorize(context.Background(), req)
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+}
+
+func TestCachedAuthorizerMaxEntries(t *testing.T) {
+	var calls int32
+	next := AuthorizerFunc(func(ctx context.Context, req *Request) (bool, error) {
+		atomic.AddInt32(&calls, 1)
+		return true, nil
+	})
+	a := NewCachedAuthorizer(next, time.Minute, WithMaxEntries(2))
+	check := func(user string) {
+		_, err := a.Authorize(context.Background(), &Request{Subject: Subject{UserID: user}, Resource: "r", Action: "a"})
+		require.NoError(t, err)
+	}
+	check("u1")
+	check("u2")
+	check("u1") // u2 becomes the least recently used
+	check("u3")
+	assert.Equal(t, 2, a.Len())
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+
+	check("u1")
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls), "u1 is still cached")
+	check("u2")
+	assert.EqualValues(t, 4, atomic.LoadInt32(&calls), "u2 was evicted")
+}
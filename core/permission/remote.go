@@ -0,0 +1,54 @@
+package permission
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/utils/httpclient"
+)
+
+// RemoteAuthorizer asks a permission service.
+// The request body is Request in json, the response body is {"allow": bool}.
+type RemoteAuthorizer struct {
+	client  httpclient.HTTPClient
+	url     string
+	headers func(ctx context.Context) map[string]string
+}
+
+var _ Authorizer = (*RemoteAuthorizer)(nil)
+
+// RemoteOption is a RemoteAuthorizer option.
+type RemoteOption func(*RemoteAuthorizer)
+
+// WithHeaders sets the headers of every check request, e.g. auth.Headers to forward the user token.
+func WithHeaders(fn func(ctx context.Context) map[string]string) RemoteOption {
+	return func(a *RemoteAuthorizer) {
+		a.headers = fn
+	}
+}
+
+// NewRemoteAuthorizer create a RemoteAuthorizer posting checks to url.
+func NewRemoteAuthorizer(client httpclient.HTTPClient, url string, opts ...RemoteOption) *RemoteAuthorizer {
+	a := &RemoteAuthorizer{client: client, url: url}
+	for _, o := range opts {
+		o(a)
+	}
+	return a
+}
+
+func (a *RemoteAuthorizer) Authorize(ctx context.Context, req *Request) (bool, error) {
+	var headers map[string]string
+	if a.headers != nil {
+		headers = a.headers(ctx)
+	}
+	_, resp, err := a.client.Post(ctx, a.url, headers, req)
+	if err != nil {
+		return false, err
+	}
+	m, ok := resp.(map[string]interface{})
+	if !ok {
+		return false, fmt.Errorf("unexpected permission service response: %v", resp)
+	}
+	allow, _ := m["allow"].(bool)
+	return allow, nil
+}
@@ -0,0 +1,50 @@
+package metric
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+// GinMiddleware records request duration, request/response size and count by
+// method, route template and status. p nil uses the default provider.
+func GinMiddleware(p *Provider) gin.HandlerFunc {
+	meter := meterOrNoop(p)
+	duration, _ := meter.Float64Histogram("http.server.request.duration", otelmetric.WithUnit("s"),
+		otelmetric.WithDescription("Duration of HTTP server requests."))
+	reqSize, _ := meter.Int64Histogram("http.server.request.body.size", otelmetric.WithUnit("By"),
+		otelmetric.WithDescription("Size of HTTP server request bodies."))
+	respSize, _ := meter.Int64Histogram("http.server.response.body.size", otelmetric.WithUnit("By"),
+		otelmetric.WithDescription("Size of HTTP server response bodies."))
+	inflight, _ := meter.Int64UpDownCounter("http.server.active_requests",
+		otelmetric.WithDescription("Number of in-flight HTTP server requests."))
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		ctx := c.Request.Context()
+		inflight.Add(ctx, 1)
+		defer inflight.Add(ctx, -1)
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		attrs := otelmetric.WithAttributes(
+			attribute.String("method", c.Request.Method),
+			attribute.String("route", route),
+			attribute.String("status", strconv.Itoa(c.Writer.Status())),
+		)
+		duration.Record(ctx, time.Since(start).Seconds(), attrs)
+		if c.Request.ContentLength >= 0 {
+			reqSize.Record(ctx, c.Request.ContentLength, attrs)
+		}
+		if size := c.Writer.Size(); size >= 0 {
+			respSize.Record(ctx, int64(size), attrs)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package metric
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"gorm.io/gorm"
+)
+
+const gormStartKey = "metric:start"
+
+// RegisterGormCallbacks records query latency by operation and table, register
+// it next to gormx.RegisterCallback once the client is created. p nil uses the default provider.
+func RegisterGormCallbacks(db *gorm.DB, p *Provider) error {
+	meter := meterOrNoop(p)
+	duration, err := meter.Float64Histogram("db.client.operation.duration", otelmetric.WithUnit("s"),
+		otelmetric.WithDescription("Duration of database operations."))
+	if err != nil {
+		return err
+	}
+	system := db.Name()
+
+	before := func(tx *gorm.DB) {
+		tx.InstanceSet(gormStartKey, time.Now())
+	}
+	after := func(operation string) func(*gorm.DB) {
+		return func(tx *gorm.DB) {
+			v, ok := tx.InstanceGet(gormStartKey)
+			if !ok {
+				return
+			}
+			start, ok := v.(time.Time)
+			if !ok {
+				return
+			}
+			duration.Record(tx.Statement.Context, time.Since(start).Seconds(), otelmetric.WithAttributes(
+				attribute.String("db_system", system),
+				attribute.String("operation", operation),
+				attribute.String("table", tx.Statement.Table),
+				errorAttr(tx.Error),
+			))
+		}
+	}
+
+	cb := db.Callback()
+	hooks := []struct {
+		operation     string
+		before, after gormRegisterer
+	}{
+		{"create", cb.Create().Before("gorm:create"), cb.Create().After("gorm:create")},
+		{"query", cb.Query().Before("gorm:query"), cb.Query().After("gorm:query")},
+		{"update", cb.Update().Before("gorm:update"), cb.Update().After("gorm:update")},
+		{"delete", cb.Delete().Before("gorm:delete"), cb.Delete().After("gorm:delete")},
+		{"row", cb.Row().Before("gorm:row"), cb.Row().After("gorm:row")},
+		{"raw", cb.Raw().Before("gorm:raw"), cb.Raw().After("gorm:raw")},
+	}
+	for _, h := range hooks {
+		if err := h.before.Register("metric:before_"+h.operation, before); err != nil {
+			return err
+		}
+		if err := h.after.Register("metric:after_"+h.operation, after(h.operation)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type gormRegisterer interface {
+	Register(name string, fn func(*gorm.DB)) error
+}
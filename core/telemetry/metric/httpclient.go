@@ -0,0 +1,34 @@
+package metric
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/utils/httpclient"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+// HTTPClientInterceptor records the duration of every attempt of an httpclient.Client by
+// method, host and status, "error" when no response was received. p nil uses the default provider.
+func HTTPClientInterceptor(p *Provider) httpclient.Interceptor {
+	duration, _ := meterOrNoop(p).Float64Histogram("http.client.request.duration", otelmetric.WithUnit("s"),
+		otelmetric.WithDescription("Duration of HTTP client requests."))
+
+	return func(req *http.Request, next httpclient.Invoker) (*http.Response, error) {
+		start := time.Now()
+		resp, err := next(req)
+		status := "error"
+		if err == nil {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+		duration.Record(req.Context(), time.Since(start).Seconds(), otelmetric.WithAttributes(
+			attribute.String("method", req.Method),
+			attribute.String("host", req.URL.Host),
+			attribute.String("status", status),
+		))
+		return resp, err
+	}
+}
@@ -0,0 +1,77 @@
+package metric
+
+import (
+	"context"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/transport/mq/kafkax"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+// WrapKafkaHandler counts consumed messages by topic and result and records the handler latency.
+// Register the wrapped handler with kafkax.Consumer.RegisterHandles. p nil uses the default provider.
+func WrapKafkaHandler(handle kafkax.MsgHandleFunc, p *Provider) kafkax.MsgHandleFunc {
+	meter := meterOrNoop(p)
+	consumed, _ := meter.Int64Counter("messaging.kafka.consumed.messages",
+		otelmetric.WithDescription("Number of kafka messages handled."))
+	duration, _ := meter.Float64Histogram("messaging.kafka.process.duration", otelmetric.WithUnit("s"),
+		otelmetric.WithDescription("Duration of kafka message handlers."))
+
+	return func(ctx context.Context, msg *kafkax.Message) bool {
+		start := time.Now()
+		ok := handle(ctx, msg)
+		attrs := otelmetric.WithAttributes(
+			attribute.String("topic", msg.Topic),
+			attribute.Bool("success", ok),
+		)
+		consumed.Add(ctx, 1, attrs)
+		duration.Record(ctx, time.Since(start).Seconds(), attrs)
+		return ok
+	}
+}
+
+// kafkaProducer counts produced messages by topic and result.
+type kafkaProducer struct {
+	kafkax.Producer
+	produced otelmetric.Int64Counter
+	duration otelmetric.Float64Histogram
+}
+
+// WrapKafkaProducer instruments a kafkax.Producer. p nil uses the default provider.
+func WrapKafkaProducer(producer kafkax.Producer, p *Provider) kafkax.Producer {
+	meter := meterOrNoop(p)
+	produced, _ := meter.Int64Counter("messaging.kafka.produced.messages",
+		otelmetric.WithDescription("Number of kafka messages produced."))
+	duration, _ := meter.Float64Histogram("messaging.kafka.publish.duration", otelmetric.WithUnit("s"),
+		otelmetric.WithDescription("Duration of kafka produce calls."))
+	return &kafkaProducer{Producer: producer, produced: produced, duration: duration}
+}
+
+func (p *kafkaProducer) Send(topic string, value []byte) error {
+	start := time.Now()
+	err := p.Producer.Send(topic, value)
+	p.record(context.Background(), topic, start, err)
+	return err
+}
+
+func (p *kafkaProducer) SendWithKey(topic string, key []byte, value []byte) error {
+	start := time.Now()
+	err := p.Producer.SendWithKey(topic, key, value)
+	p.record(context.Background(), topic, start, err)
+	return err
+}
+
+func (p *kafkaProducer) RetrySend(ctx context.Context, topic string, messageBody []byte) error {
+	start := time.Now()
+	err := p.Producer.RetrySend(ctx, topic, messageBody)
+	p.record(ctx, topic, start, err)
+	return err
+}
+
+func (p *kafkaProducer) record(ctx context.Context, topic string, start time.Time, err error) {
+	attrs := otelmetric.WithAttributes(attribute.String("topic", topic), errorAttr(err))
+	p.produced.Add(ctx, 1, attrs)
+	p.duration.Record(ctx, time.Since(start).Seconds(), attrs)
+}
@@ -0,0 +1,155 @@
+package metric
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const (
+	// DefaultPath is where the prometheus handler is usually mounted on the admin port.
+	DefaultPath = "/metrics"
+
+	instrumentationName = "github.com/kweaver-ai/idrm-go-frame/core/telemetry/metric"
+)
+
+// DefaultDurationBuckets histogram buckets of latencies in seconds.
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// DefaultSizeBuckets histogram buckets of payload sizes in bytes.
+var DefaultSizeBuckets = []float64{128, 512, 1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, 1 << 20, 4 << 20}
+
+// Config metric 配置
+type Config struct {
+	ServerName    string `json:"serverName"`
+	ServerVersion string `json:"serverVersion"`
+	// Namespace prefixes every exported metric name, e.g. idrm
+	Namespace string `json:"namespace"`
+	// DisableRuntime turns off the go runtime metrics
+	DisableRuntime bool `json:"disableRuntime,string"`
+}
+
+// Provider owns the meter provider and the prometheus reader.
+type Provider struct {
+	conf     Config
+	reader   *sdkmetric.ManualReader
+	provider *sdkmetric.MeterProvider
+	meter    otelmetric.Meter
+}
+
+var (
+	mu       sync.RWMutex
+	provider *Provider
+)
+
+// New create a metric provider, Init also installs it globally.
+func New(conf Config) (*Provider, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(conf.ServerName),
+		semconv.ServiceVersion(conf.ServerVersion),
+	))
+	if err != nil {
+		return nil, err
+	}
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(reader),
+		sdkmetric.WithResource(res),
+		sdkmetric.WithView(durationView(), sizeView()),
+	)
+	p := &Provider{
+		conf:     conf,
+		reader:   reader,
+		provider: mp,
+		meter:    mp.Meter(instrumentationName),
+	}
+	if !conf.DisableRuntime {
+		if err := registerRuntime(p.meter); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Init create the provider and installs it as the default and otel global meter provider.
+func Init(conf Config) (*Provider, error) {
+	p, err := New(conf)
+	if err != nil {
+		return nil, err
+	}
+	mu.Lock()
+	provider = p
+	mu.Unlock()
+	otel.SetMeterProvider(p.provider)
+	return p, nil
+}
+
+// Default returns the provider installed by Init, nil before Init.
+func Default() *Provider {
+	mu.RLock()
+	defer mu.RUnlock()
+	return provider
+}
+
+// Meter returns a meter of the provider.
+func (p *Provider) Meter(name string, opts ...otelmetric.MeterOption) otelmetric.Meter {
+	return p.provider.Meter(name, opts...)
+}
+
+// MeterProvider returns the underlying otel meter provider.
+func (p *Provider) MeterProvider() otelmetric.MeterProvider {
+	return p.provider
+}
+
+// Handler serves the prometheus text format, mount it on the admin port:
+//
+//	mux.Handle(metric.DefaultPath, p.Handler())
+func (p *Provider) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := p.WritePrometheus(r.Context(), w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// Shutdown flushes and stops the provider.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.provider.Shutdown(ctx)
+}
+
+// meterOrNoop returns the meter of p, falling back to the default provider and then to the otel global one.
+func meterOrNoop(p *Provider) otelmetric.Meter {
+	if p == nil {
+		p = Default()
+	}
+	if p == nil {
+		return otel.GetMeterProvider().Meter(instrumentationName)
+	}
+	return p.meter
+}
+
+func durationView() sdkmetric.View {
+	return sdkmetric.NewView(
+		sdkmetric.Instrument{Unit: "s", Kind: sdkmetric.InstrumentKindHistogram},
+		sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: DefaultDurationBuckets}},
+	)
+}
+
+func sizeView() sdkmetric.View {
+	return sdkmetric.NewView(
+		sdkmetric.Instrument{Unit: "By", Kind: sdkmetric.InstrumentKindHistogram},
+		sdkmetric.Stream{Aggregation: sdkmetric.AggregationExplicitBucketHistogram{Boundaries: DefaultSizeBuckets}},
+	)
+}
+
+func errorAttr(err error) attribute.KeyValue {
+	return attribute.Bool("error", err != nil)
+}
@@ -0,0 +1,80 @@
+package metric
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/transport/mq/kafkax"
+	"github.com/kweaver-ai/idrm-go-frame/core/utils/httpclient"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	p, err := New(Config{ServerName: "test", Namespace: "idrm"})
+	require.NoError(t, err)
+
+	r := gin.New()
+	r.Use(GinMiddleware(p))
+	r.GET("/api/v1/users/:id", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+
+	for _, path := range []string{"/api/v1/users/1", "/api/v1/users/2", "/missing"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	p.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, DefaultPath, nil))
+	body := w.Body.String()
+
+	assert.Contains(t, body, "# TYPE idrm_http_server_request_duration_seconds histogram")
+	assert.Contains(t, body, `idrm_http_server_request_duration_seconds_count{method="GET",route="/api/v1/users/:id",status="200"} 2`)
+	assert.Contains(t, body, `route="unmatched",status="404"`)
+	assert.Contains(t, body, `le="+Inf"`)
+	assert.Contains(t, body, "idrm_go_goroutines")
+}
+
+func TestKafkaHandler(t *testing.T) {
+	p, err := New(Config{DisableRuntime: true})
+	require.NoError(t, err)
+
+	handle := WrapKafkaHandler(func(ctx context.Context, msg *kafkax.Message) bool {
+		return string(msg.Value) == "ok"
+	}, p)
+	handle(context.Background(), &kafkax.Message{Topic: "events", Value: []byte("ok")})
+	handle(context.Background(), &kafkax.Message{Topic: "events", Value: []byte("bad")})
+
+	var b strings.Builder
+	require.NoError(t, p.WritePrometheus(context.Background(), &b))
+	assert.Contains(t, b.String(), `messaging_kafka_consumed_messages_total{success="true",topic="events"} 1`)
+	assert.Contains(t, b.String(), `messaging_kafka_consumed_messages_total{success="false",topic="events"} 1`)
+	assert.NotContains(t, b.String(), "go_goroutines")
+}
+
+func TestErrorAttr(t *testing.T) {
+	assert.Equal(t, "false", errorAttr(nil).Value.Emit())
+	assert.Equal(t, "true", errorAttr(errors.New("boom")).Value.Emit())
+}
+
+func TestHTTPClientInterceptor(t *testing.T) {
+	p, err := New(Config{DisableRuntime: true})
+	require.NoError(t, err)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c := httpclient.New(httpclient.WithInterceptors(HTTPClientInterceptor(p)))
+	_, err = c.Do(context.Background(), &httpclient.Request{Method: http.MethodGet, URL: srv.URL})
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, p.WritePrometheus(context.Background(), &b))
+	assert.Contains(t, b.String(), `http_client_request_duration_seconds_count{host="`+strings.TrimPrefix(srv.URL, "http://")+`",method="GET",status="204"} 1`)
+}
@@ -0,0 +1,251 @@
+package metric
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// WritePrometheus collects the current values and writes them in the prometheus text format 0.0.4.
+func (p *Provider) WritePrometheus(ctx context.Context, w io.Writer) error {
+	rm := metricdata.ResourceMetrics{}
+	if err := p.reader.Collect(ctx, &rm); err != nil {
+		return err
+	}
+	var families []*family
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if f := p.toFamily(m); f != nil {
+				families = append(families, f)
+			}
+		}
+	}
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw)
+	}
+	return bw.Flush()
+}
+
+type sample struct {
+	suffix string
+	labels []label
+	value  float64
+}
+
+type label struct {
+	name, value string
+}
+
+type family struct {
+	name    string
+	help    string
+	typ     string
+	samples []sample
+}
+
+func (p *Provider) toFamily(m metricdata.Metrics) *family {
+	f := &family{name: p.metricName(m.Name, m.Unit), help: m.Description}
+	switch data := m.Data.(type) {
+	case metricdata.Sum[int64]:
+		f.fromSum(data.IsMonotonic, toFloatPoints(data.DataPoints))
+	case metricdata.Sum[float64]:
+		f.fromSum(data.IsMonotonic, toFloatPoints(data.DataPoints))
+	case metricdata.Gauge[int64]:
+		f.typ = "gauge"
+		f.fromPoints(toFloatPoints(data.DataPoints))
+	case metricdata.Gauge[float64]:
+		f.typ = "gauge"
+		f.fromPoints(toFloatPoints(data.DataPoints))
+	case metricdata.Histogram[int64]:
+		f.fromHistogram(toFloatHistogram(data.DataPoints))
+	case metricdata.Histogram[float64]:
+		f.fromHistogram(toFloatHistogram(data.DataPoints))
+	default:
+		return nil
+	}
+	return f
+}
+
+type floatPoint struct {
+	attrs attribute.Set
+	value float64
+}
+
+type floatHistogramPoint struct {
+	attrs  attribute.Set
+	bounds []float64
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func toFloatPoints[N int64 | float64](dps []metricdata.DataPoint[N]) []floatPoint {
+	points := make([]floatPoint, 0, len(dps))
+	for _, dp := range dps {
+		points = append(points, floatPoint{attrs: dp.Attributes, value: float64(dp.Value)})
+	}
+	return points
+}
+
+func toFloatHistogram[N int64 | float64](dps []metricdata.HistogramDataPoint[N]) []floatHistogramPoint {
+	points := make([]floatHistogramPoint, 0, len(dps))
+	for _, dp := range dps {
+		points = append(points, floatHistogramPoint{
+			attrs:  dp.Attributes,
+			bounds: dp.Bounds,
+			counts: dp.BucketCounts,
+			count:  dp.Count,
+			sum:    float64(dp.Sum),
+		})
+	}
+	return points
+}
+
+func (f *family) fromSum(monotonic bool, points []floatPoint) {
+	if monotonic {
+		f.typ = "counter"
+		if !strings.HasSuffix(f.name, "_total") {
+			f.name += "_total"
+		}
+	} else {
+		f.typ = "gauge"
+	}
+	f.fromPoints(points)
+}
+
+func (f *family) fromPoints(points []floatPoint) {
+	for _, pt := range points {
+		f.samples = append(f.samples, sample{labels: toLabels(pt.attrs), value: pt.value})
+	}
+}
+
+func (f *family) fromHistogram(points []floatHistogramPoint) {
+	f.typ = "histogram"
+	for _, pt := range points {
+		labels := toLabels(pt.attrs)
+		var cumulative uint64
+		for i, bound := range pt.bounds {
+			cumulative += pt.counts[i]
+			f.samples = append(f.samples, sample{
+				suffix: "_bucket",
+				labels: append(append([]label(nil), labels...), label{"le", formatFloat(bound)}),
+				value:  float64(cumulative),
+			})
+		}
+		f.samples = append(f.samples,
+			sample{suffix: "_bucket", labels: append(append([]label(nil), labels...), label{"le", "+Inf"}), value: float64(pt.count)},
+			sample{suffix: "_sum", labels: labels, value: pt.sum},
+			sample{suffix: "_count", labels: labels, value: float64(pt.count)},
+		)
+	}
+}
+
+func (f *family) write(w *bufio.Writer) {
+	if f.help != "" {
+		fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	}
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.typ)
+	for _, s := range f.samples {
+		w.WriteString(f.name)
+		w.WriteString(s.suffix)
+		if len(s.labels) > 0 {
+			w.WriteByte('{')
+			for i, l := range s.labels {
+				if i > 0 {
+					w.WriteByte(',')
+				}
+				w.WriteString(l.name)
+				w.WriteString(`="`)
+				w.WriteString(escapeLabelValue(l.value))
+				w.WriteByte('"')
+			}
+			w.WriteByte('}')
+		}
+		w.WriteByte(' ')
+		w.WriteString(formatFloat(s.value))
+		w.WriteByte('\n')
+	}
+}
+
+func (p *Provider) metricName(name, unit string) string {
+	n := sanitizeName(name)
+	if p.conf.Namespace != "" {
+		n = sanitizeName(p.conf.Namespace) + "_" + n
+	}
+	switch unit {
+	case "s":
+		if !strings.HasSuffix(n, "_seconds") {
+			n += "_seconds"
+		}
+	case "By":
+		if !strings.HasSuffix(n, "_bytes") {
+			n += "_bytes"
+		}
+	}
+	return n
+}
+
+func toLabels(set attribute.Set) []label {
+	labels := make([]label, 0, set.Len())
+	iter := set.Iter()
+	for iter.Next() {
+		kv := iter.Attribute()
+		labels = append(labels, label{name: sanitizeName(string(kv.Key)), value: kv.Value.Emit()})
+	}
+	return labels
+}
+
+// sanitizeName maps otel names like http.server.duration to prometheus names like http_server_duration.
+func sanitizeName(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_', r == ':':
+			b.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabelValue(s string) string {
+	return labelReplacer.Replace(s)
+}
@@ -0,0 +1,88 @@
+package metric
+
+import (
+	"context"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/redis_tool"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+type redisStartKey struct{}
+
+// redisHook records the latency of redis commands and pipelines.
+type redisHook struct {
+	duration otelmetric.Float64Histogram
+}
+
+var _ redis.Hook = (*redisHook)(nil)
+
+// NewRedisHook returns a go-redis hook recording command latency. p nil uses the default provider.
+func NewRedisHook(p *Provider) (redis.Hook, error) {
+	duration, err := meterOrNoop(p).Float64Histogram("redis.client.command.duration", otelmetric.WithUnit("s"),
+		otelmetric.WithDescription("Duration of redis commands."))
+	if err != nil {
+		return nil, err
+	}
+	return &redisHook{duration: duration}, nil
+}
+
+// InstrumentRedis adds the metric hook to the read and write clients of redis_tool.
+func InstrumentRedis(r *redis_tool.Redis, p *Provider) error {
+	hook, err := NewRedisHook(p)
+	if err != nil {
+		return err
+	}
+	type hooker interface{ AddHook(redis.Hook) }
+	if h, ok := r.Write.(hooker); ok {
+		h.AddHook(hook)
+	}
+	if r.Read != r.Write {
+		if h, ok := r.Read.(hooker); ok {
+			h.AddHook(hook)
+		}
+	}
+	return nil
+}
+
+func (h *redisHook) BeforeProcess(ctx context.Context, _ redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (h *redisHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	h.record(ctx, cmd.Name(), cmd.Err())
+	return nil
+}
+
+func (h *redisHook) BeforeProcessPipeline(ctx context.Context, _ []redis.Cmder) (context.Context, error) {
+	return context.WithValue(ctx, redisStartKey{}, time.Now()), nil
+}
+
+func (h *redisHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil && cmd.Err() != redis.Nil {
+			err = cmd.Err()
+			break
+		}
+	}
+	h.record(ctx, "pipeline", err)
+	return nil
+}
+
+func (h *redisHook) record(ctx context.Context, command string, err error) {
+	start, ok := ctx.Value(redisStartKey{}).(time.Time)
+	if !ok {
+		return
+	}
+	if err == redis.Nil {
+		err = nil
+	}
+	h.duration.Record(ctx, time.Since(start).Seconds(), otelmetric.WithAttributes(
+		attribute.String("command", command),
+		errorAttr(err),
+	))
+}
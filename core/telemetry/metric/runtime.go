@@ -0,0 +1,62 @@
+package metric
+
+import (
+	"context"
+	"runtime"
+	"time"
+
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+// registerRuntime observes the go runtime on every collection.
+func registerRuntime(meter otelmetric.Meter) error {
+	goroutines, err := meter.Int64ObservableGauge("go.goroutines",
+		otelmetric.WithDescription("Number of goroutines that currently exist."))
+	if err != nil {
+		return err
+	}
+	heapAlloc, err := meter.Int64ObservableGauge("go.memory.heap_alloc", otelmetric.WithUnit("By"),
+		otelmetric.WithDescription("Bytes of allocated heap objects."))
+	if err != nil {
+		return err
+	}
+	heapSys, err := meter.Int64ObservableGauge("go.memory.heap_sys", otelmetric.WithUnit("By"),
+		otelmetric.WithDescription("Bytes of heap memory obtained from the OS."))
+	if err != nil {
+		return err
+	}
+	heapObjects, err := meter.Int64ObservableGauge("go.memory.heap_objects",
+		otelmetric.WithDescription("Number of allocated heap objects."))
+	if err != nil {
+		return err
+	}
+	gcCount, err := meter.Int64ObservableCounter("go.gc.cycles",
+		otelmetric.WithDescription("Number of completed GC cycles."))
+	if err != nil {
+		return err
+	}
+	gcPause, err := meter.Float64ObservableCounter("go.gc.pause", otelmetric.WithUnit("s"),
+		otelmetric.WithDescription("Cumulative time spent in GC stop-the-world pauses."))
+	if err != nil {
+		return err
+	}
+	threads, err := meter.Int64ObservableGauge("go.gomaxprocs",
+		otelmetric.WithDescription("Value of GOMAXPROCS."))
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o otelmetric.Observer) error {
+		var ms runtime.MemStats
+		runtime.ReadMemStats(&ms)
+		o.ObserveInt64(goroutines, int64(runtime.NumGoroutine()))
+		o.ObserveInt64(heapAlloc, int64(ms.HeapAlloc))
+		o.ObserveInt64(heapSys, int64(ms.HeapSys))
+		o.ObserveInt64(heapObjects, int64(ms.HeapObjects))
+		o.ObserveInt64(gcCount, int64(ms.NumGC))
+		o.ObserveFloat64(gcPause, time.Duration(ms.PauseTotalNs).Seconds())
+		o.ObserveInt64(threads, int64(runtime.GOMAXPROCS(0)))
+		return nil
+	}, goroutines, heapAlloc, heapSys, heapObjects, gcCount, gcPause, threads)
+	return err
+}
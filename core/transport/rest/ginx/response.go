@@ -2,7 +2,9 @@ package ginx
 
 import (
 	"net/http"
+	"sync/atomic"
 
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx"
 	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
 	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
 
@@ -11,12 +13,27 @@ import (
 
 const StatusCode = "StatusCode"
 
+var codeStatus atomic.Bool
+
+// SetCodeStatus makes ResErrJson, and rest.ResErrJson, answer an error with the HTTP status
+// of its code, see agcodes.HTTPStatus. Off by default: ginx keeps the status of the response,
+// 200 unless set, and rest answers 400.
+func SetCodeStatus(enabled bool) {
+	codeStatus.Store(enabled)
+}
+
+// CodeStatus reports whether SetCodeStatus is enabled.
+func CodeStatus() bool {
+	return codeStatus.Load()
+}
+
 type HttpError struct {
 	Code        string      `json:"code"`
 	Description string      `json:"description"`
 	Solution    string      `json:"solution,omitempty"`
 	Cause       string      `json:"cause,omitempty"`
 	Detail      interface{} `json:"detail,omitempty"`
+	Link        string      `json:"link,omitempty"`
 	Data        interface{} `json:"data,omitempty"`
 }
 
@@ -58,14 +75,20 @@ func ResErrJson(c *gin.Context, err error) {
 		code = agcodes.CodeOK
 	} else {
 		code = agerrors.Code(err)
+		// an error answered without a status takes the one of its code
+		if status := explicitStatus(code); CodeStatus() && status != 0 && !c.Writer.Written() && c.Writer.Status() == http.StatusOK {
+			c.Writer.WriteHeader(status)
+		}
 	}
 
+	code = errorx.Localize(c, code)
 	c.JSON(c.Writer.Status(), HttpError{
 		Code:        code.GetErrorCode(),
 		Description: code.GetDescription(),
 		Solution:    code.GetSolution(),
 		Cause:       code.GetCause(),
 		Detail:      code.GetErrorDetails(),
+		Link:        code.GetErrorLink(),
 	})
 }
 
@@ -78,11 +101,21 @@ func AbortResponse(c *gin.Context, err error) {
 	if err == nil {
 		code = agcodes.CodeNotAuthorized
 	}
+	code = errorx.Localize(c, code)
 	c.AbortWithStatusJSON(c.Writer.Status(), HttpError{
 		Code:        code.GetErrorCode(),
 		Description: code.GetDescription(),
 		Solution:    code.GetSolution(),
 		Cause:       code.GetCause(),
 		Detail:      code.GetErrorDetails(),
+		Link:        code.GetErrorLink(),
 	})
 }
+
+// explicitStatus returns the HTTP status set on code, 0 when it has none.
+func explicitStatus(code agcodes.Coder) int {
+	if sc, ok := code.(agcodes.StatusCoder); ok {
+		return sc.GetHTTPStatus()
+	}
+	return 0
+}
@@ -0,0 +1,43 @@
+package ginx
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResErrJsonStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/not-found", func(c *gin.Context) {
+		ResErrJson(c, agerrors.NewCode(agcodes.CodeNotFound))
+	})
+	r.GET("/plain", func(c *gin.Context) {
+		ResErrJson(c, errors.New("boom"))
+	})
+	r.GET("/bad-request", func(c *gin.Context) {
+		ResBadRequestJson(c, agerrors.NewCode(agcodes.CodeNotFound))
+	})
+	do := func(target string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, do("/not-found"))
+	assert.Equal(t, http.StatusOK, do("/plain"))
+	assert.Equal(t, http.StatusBadRequest, do("/bad-request"))
+
+	SetCodeStatus(true)
+	defer SetCodeStatus(false)
+	assert.Equal(t, http.StatusNotFound, do("/not-found"))
+	assert.Equal(t, http.StatusOK, do("/plain"))
+	assert.Equal(t, http.StatusBadRequest, do("/bad-request"))
+}
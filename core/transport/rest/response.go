@@ -3,8 +3,10 @@ package rest
 import (
 	"net/http"
 
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx"
 	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
 	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+	"github.com/kweaver-ai/idrm-go-frame/core/transport/rest/ginx"
 
 	"github.com/gin-gonic/gin"
 )
@@ -33,6 +35,9 @@ func ResErrJson(c *gin.Context, err error) {
 		if code == agcodes.CodeNil {
 			code = agcodes.CodeInternalError
 		}
+		if ginx.CodeStatus() {
+			statusCode = agcodes.HTTPStatus(code)
+		}
 	} else if c.Writer.Status() > 0 && c.Writer.Status() != http.StatusOK {
 		//switch c.Writer.Status() {
 		//case http.StatusNotFound:
@@ -49,6 +54,7 @@ func ResErrJson(c *gin.Context, err error) {
 		statusCode = 200
 	}
 
+	code = errorx.Localize(c, code)
 	c.JSON(statusCode, HttpError{
 		Code:        code.GetErrorCode(),
 		Description: code.GetDescription(),
@@ -4,12 +4,14 @@ import (
     "context"
     "testing"
     "time"
+
+    "github.com/gin-gonic/gin"
 )
 
 func TestServer(t *testing.T){
 
     ctx := context.Background()
-    srv := NewServer()
+    srv := NewServer(gin.New())
     //srv.Handle("/index", newHandleFuncWrapper(h))
     //srv.HandleFunc("/index/{id:[0-9]+}", h)
     //srv.HandlePrefix("/test/prefix", newHandleFuncWrapper(h))
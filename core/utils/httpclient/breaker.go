@@ -0,0 +1,120 @@
+package httpclient
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+)
+
+// ErrCircuitOpen the host failed too often, the call was not sent.
+// The returned error has code agcodes.CodeServiceUnavailable and matches ErrCircuitOpen with errors.Is.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerConfig circuit breaker of a host. Network errors and 5xx responses are failures.
+// After FailureThreshold failures in a row calls fail fast for OpenTimeout, then one probe
+// is let through: its success closes the breaker, its failure opens it again.
+type BreakerConfig struct {
+	// FailureThreshold 0 disables the breaker
+	FailureThreshold int
+	OpenTimeout      time.Duration
+}
+
+// DefaultBreakerConfig opens after 5 failures in a row for 30s.
+var DefaultBreakerConfig = BreakerConfig{FailureThreshold: 5, OpenTimeout: 30 * time.Second}
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+type breaker struct {
+	conf BreakerConfig
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+// allow reports whether a call may be sent, every allowed call must be followed by done.
+func (b *breaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case stateOpen:
+		if time.Since(b.openedAt) < b.conf.OpenTimeout {
+			return false
+		}
+		b.state = stateHalfOpen
+		return true
+	case stateHalfOpen:
+		// a probe is in flight
+		return false
+	}
+	return true
+}
+
+func (b *breaker) done(failed bool) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.state, b.failures = stateClosed, 0
+		return
+	}
+	b.failures++
+	if b.state == stateHalfOpen || b.failures >= b.conf.FailureThreshold {
+		b.state, b.openedAt = stateOpen, time.Now()
+	}
+}
+
+// abort ends an allowed call that neither succeeded nor failed, a probe is let through again.
+func (b *breaker) abort() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == stateHalfOpen {
+		b.state = stateOpen
+	}
+}
+
+type breakers struct {
+	conf  BreakerConfig
+	hosts sync.Map
+}
+
+func newBreakers(conf BreakerConfig) *breakers {
+	if conf.OpenTimeout <= 0 {
+		conf.OpenTimeout = DefaultBreakerConfig.OpenTimeout
+	}
+	return &breakers{conf: conf}
+}
+
+// get returns the breaker of host, nil when breaking is disabled.
+func (bs *breakers) get(host string) *breaker {
+	if bs.conf.FailureThreshold <= 0 {
+		return nil
+	}
+	if b, ok := bs.hosts.Load(host); ok {
+		return b.(*breaker)
+	}
+	b, _ := bs.hosts.LoadOrStore(host, &breaker{conf: bs.conf})
+	return b.(*breaker)
+}
+
+func circuitOpenError(host string) error {
+	return agerrors.WrapCode(agcodes.CodeServiceUnavailable, ErrCircuitOpen, host+": circuit breaker is open")
+}
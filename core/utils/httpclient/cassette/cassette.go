@@ -0,0 +1,326 @@
+// Package cassette records the HTTP interactions of a test to a golden file and replays them offline.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay answers from the cassette only.
+	ModeReplay Mode = iota
+	// ModeRecord sends every request and records it, the cassette is rewritten by Save.
+	ModeRecord
+	// ModeAuto replays when the cassette exists and records otherwise.
+	ModeAuto
+)
+
+// Redacted replaces the value of redacted headers in the cassette.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactHeaders headers never written to a cassette.
+var DefaultRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "Proxy-Authorization"}
+
+// Cassette the recorded interactions, the golden file content.
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction a request and the response it got.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Matcher reports whether the recorded interaction answers req, body is the request body.
+type Matcher func(req *http.Request, body []byte, recorded *RecordedRequest) bool
+
+// Recorder an http.RoundTripper recording to or replaying from a cassette file.
+type Recorder struct {
+	path      string
+	mode      Mode
+	strict    bool
+	transport http.RoundTripper
+	redact    map[string]bool
+	matcher   Matcher
+
+	mu        sync.Mutex
+	cassette  *Cassette
+	used      []bool
+	unmatched []string
+}
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithMode sets the mode, ModeAuto by default.
+func WithMode(m Mode) Option {
+	return func(r *Recorder) { r.mode = m }
+}
+
+// WithStrict fails requests missing from the cassette instead of sending them.
+func WithStrict() Option {
+	return func(r *Recorder) { r.strict = true }
+}
+
+// WithTransport sets the transport sending real requests, http.DefaultTransport by default.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(r *Recorder) { r.transport = rt }
+}
+
+// WithRedactHeaders adds headers whose value is not written to the cassette.
+func WithRedactHeaders(headers ...string) Option {
+	return func(r *Recorder) {
+		for _, h := range headers {
+			r.redact[http.CanonicalHeaderKey(h)] = true
+		}
+	}
+}
+
+// WithMatcher replaces DefaultMatcher.
+func WithMatcher(m Matcher) Option {
+	return func(r *Recorder) { r.matcher = m }
+}
+
+// New creates a recorder of the cassette file at path.
+func New(path string, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      ModeAuto,
+		transport: http.DefaultTransport,
+		redact:    make(map[string]bool),
+		matcher:   DefaultMatcher,
+		cassette:  &Cassette{},
+	}
+	for _, h := range DefaultRedactHeaders {
+		r.redact[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if r.mode == ModeAuto {
+			r.mode = ModeReplay
+		}
+	case os.IsNotExist(err):
+		if r.mode == ModeReplay {
+			return nil, fmt.Errorf("cassette: %s not found, record it first", path)
+		}
+		r.mode = ModeRecord
+	default:
+		return nil, err
+	}
+	if r.mode == ModeReplay {
+		if err := json.Unmarshal(data, r.cassette); err != nil {
+			return nil, fmt.Errorf("cassette: parse %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the effective mode, ModeRecord or ModeReplay.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Client returns an *http.Client using the recorder.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip answers from the cassette in replay mode, sends and records the request in record mode.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeReplay {
+		if recorded := r.match(req, body); recorded != nil {
+			return recorded.response(req), nil
+		}
+		if r.strict {
+			r.mu.Lock()
+			r.unmatched = append(r.unmatched, req.Method+" "+req.URL.String())
+			r.mu.Unlock()
+			return nil, fmt.Errorf("cassette: no interaction recorded in %s for %s %s", r.path, req.Method, req.URL)
+		}
+		return r.transport.RoundTrip(req)
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: r.redactHeader(req.Header),
+			Body:   string(body),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     r.redactHeader(resp.Header),
+			Body:       string(respBody),
+		},
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// match returns the first unused interaction matching req, the last used one when all were used.
+func (r *Recorder) match(req *http.Request, body []byte) *RecordedResponse {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, it := range r.cassette.Interactions {
+		if !r.matcher(req, body, &it.Request) {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return &it.Response
+		}
+		last = i
+	}
+	if last < 0 {
+		return nil
+	}
+	return &r.cassette.Interactions[last].Response
+}
+
+// Unmatched returns the requests refused in strict replay mode.
+func (r *Recorder) Unmatched() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.unmatched...)
+}
+
+// Save writes the recorded interactions to the cassette file, it does nothing in replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+func (r *Recorder) redactHeader(h http.Header) http.Header {
+	if len(h) == 0 {
+		return nil
+	}
+	out := make(http.Header, len(h))
+	for k, vs := range h {
+		if r.redact[http.CanonicalHeaderKey(k)] {
+			out[k] = []string{Redacted}
+			continue
+		}
+		out[k] = append([]string(nil), vs...)
+	}
+	return out
+}
+
+func (rr *RecordedResponse) response(req *http.Request) *http.Response {
+	header := rr.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", rr.StatusCode, http.StatusText(rr.StatusCode)),
+		StatusCode:    rr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(rr.Body)),
+		ContentLength: int64(len(rr.Body)),
+		Request:       req,
+	}
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// DefaultMatcher matches the method, the path, the query and the body, json bodies are compared by value.
+func DefaultMatcher(req *http.Request, body []byte, recorded *RecordedRequest) bool {
+	if req.Method != recorded.Method {
+		return false
+	}
+	u, err := req.URL.Parse(recorded.URL)
+	if err != nil || u.Path != req.URL.Path || u.Query().Encode() != req.URL.Query().Encode() {
+		return false
+	}
+	return sameBody(body, []byte(recorded.Body))
+}
+
+// MethodPathMatcher matches the method and the path only.
+func MethodPathMatcher(req *http.Request, _ []byte, recorded *RecordedRequest) bool {
+	u, err := req.URL.Parse(recorded.URL)
+	return err == nil && req.Method == recorded.Method && u.Path == req.URL.Path
+}
+
+func sameBody(a, b []byte) bool {
+	if bytes.Equal(a, b) {
+		return true
+	}
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	ca, _ := json.Marshal(va)
+	cb, _ := json.Marshal(vb)
+	return bytes.Equal(ca, cb)
+}
@@ -0,0 +1,114 @@
+package cassette
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/utils/httpclient"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"path":"` + r.URL.Path + `","body":` + string(orNull(body)) + `}`))
+	}))
+}
+
+func orNull(b []byte) []byte {
+	if len(b) == 0 {
+		return []byte("null")
+	}
+	return b
+}
+
+func do(t *testing.T, c *http.Client, method, url, body string) string {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.json")
+	srv := newServer()
+
+	rec, err := New(path)
+	require.NoError(t, err)
+	assert.Equal(t, ModeRecord, rec.Mode())
+	c := rec.Client()
+	assert.Equal(t, `{"path":"/users","body":{"name":"a","age":1}}`, do(t, c, http.MethodPost, srv.URL+"/users", `{"name":"a","age":1}`))
+	assert.Equal(t, `{"path":"/users/1","body":null}`, do(t, c, http.MethodGet, srv.URL+"/users/1", ""))
+	require.NoError(t, rec.Save())
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	assert.Contains(t, string(data), Redacted)
+
+	replay, err := New(path, WithStrict())
+	require.NoError(t, err)
+	assert.Equal(t, ModeReplay, replay.Mode())
+	c = replay.Client()
+	// json bodies match by value
+	assert.Equal(t, `{"path":"/users","body":{"name":"a","age":1}}`, do(t, c, http.MethodPost, srv.URL+"/users", `{"age":1, "name":"a"}`))
+	assert.Equal(t, `{"path":"/users/1","body":null}`, do(t, c, http.MethodGet, srv.URL+"/users/1", ""))
+
+	_, err = c.Post(srv.URL+"/users", "application/json", strings.NewReader(`{"name":"b"}`))
+	assert.ErrorContains(t, err, "no interaction recorded")
+	_, err = c.Get(srv.URL + "/users/2")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"POST " + srv.URL + "/users", "GET " + srv.URL + "/users/2"}, replay.Unmatched())
+}
+
+func TestReplayMissing(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"), WithMode(ModeReplay))
+	assert.ErrorContains(t, err, "record it first")
+}
+
+func TestUse(t *testing.T) {
+	old := Dir
+	Dir = t.TempDir()
+	defer func() { Dir = old }()
+	srv := newServer()
+	c := httpclient.New(httpclient.WithBaseURL(srv.URL))
+
+	type reply struct {
+		Path string `json:"path"`
+	}
+	t.Run("record", func(t *testing.T) {
+		rec := Use(t, c.HTTPClient(), "typed")
+		assert.Equal(t, ModeRecord, rec.Mode())
+		r, err := httpclient.Get[reply](context.Background(), c, "/views/1")
+		require.NoError(t, err)
+		assert.Equal(t, "/views/1", r.Path)
+	})
+	srv.Close()
+	assert.FileExists(t, filepath.Join(Dir, "typed.json"))
+	_, recording := c.HTTPClient().Transport.(*Recorder)
+	assert.False(t, recording, "transport restored")
+
+	t.Run("replay", func(t *testing.T) {
+		rec := Use(t, c.HTTPClient(), "typed", WithStrict())
+		assert.Equal(t, ModeReplay, rec.Mode())
+		r, err := httpclient.Get[reply](context.Background(), c, "/views/1")
+		require.NoError(t, err)
+		assert.Equal(t, "/views/1", r.Path)
+	})
+}
@@ -0,0 +1,60 @@
+package cassette
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// EnvMode environment variable forcing the mode of Use: "record", "replay" or "auto".
+const EnvMode = "HTTP_CASSETTE"
+
+// Dir directory of the cassettes of Use, relative to the package under test.
+var Dir = filepath.Join("testdata", "cassettes")
+
+// Use plugs the cassette testdata/cassettes/<name>.json into client until the end of the test,
+// any *http.Client works: httpclient.NewRawHTTPClient(), the one given to
+// httpclient.NewMiddlewareHTTPClient, httpclient.Client.HTTPClient() or trace.NewOtelHttpClient().
+// The transport of client sends the real requests while recording, it is restored on cleanup
+// and the cassette is saved. Run the tests with HTTP_CASSETTE=record to record again.
+func Use(t testing.TB, client *http.Client, name string, opts ...Option) *Recorder {
+	t.Helper()
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	opts = append([]Option{WithTransport(base)}, opts...)
+	switch os.Getenv(EnvMode) {
+	case "record":
+		opts = append(opts, WithMode(ModeRecord))
+	case "replay":
+		opts = append(opts, WithMode(ModeReplay))
+	case "auto":
+		opts = append(opts, WithMode(ModeAuto))
+	}
+
+	r, err := New(filepath.Join(Dir, name+".json"), opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Transport = r
+	t.Cleanup(func() {
+		client.Transport = base
+		if err := r.Save(); err != nil {
+			t.Errorf("cassette: save %s: %v", name, err)
+		}
+		for _, req := range r.Unmatched() {
+			t.Errorf("cassette: unmatched request %s", req)
+		}
+	})
+	return r
+}
+
+// NewClient returns an *http.Client replaying the cassette name, see Use.
+func NewClient(t testing.TB, name string, opts ...Option) *http.Client {
+	t.Helper()
+	client := &http.Client{}
+	Use(t, client, name, opts...)
+	return client
+}
@@ -0,0 +1,314 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Client JSON HTTP 客户端, 支持拦截器链、超时、幂等请求重试和按 host 熔断.
+// 与 HTTPClient 不同, 每个 Client 相互独立, 使用各自的 *http.Client.
+type Client struct {
+	client       *http.Client
+	baseURL      string
+	timeout      time.Duration
+	retry        RetryPolicy
+	breaker      BreakerConfig
+	interceptors []Interceptor
+	invoke       Invoker
+	breakers     *breakers
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the *http.Client sending the requests, e.g. one skipping the certificate
+// checks of a test server. By default a clone of http.DefaultTransport verifies them.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.client = hc }
+}
+
+// WithBaseURL sets the prefix of relative request urls.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) { c.baseURL = strings.TrimRight(baseURL, "/") }
+}
+
+// WithTimeout sets the default timeout of a call, retries included. 0 disables it.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) { c.timeout = d }
+}
+
+// WithRetry sets the retry policy of idempotent requests.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) { c.retry = p }
+}
+
+// WithBreaker sets the circuit breaker kept for every host.
+func WithBreaker(conf BreakerConfig) Option {
+	return func(c *Client) { c.breaker = conf }
+}
+
+// WithInterceptors appends interceptors, the first one is the outermost.
+func WithInterceptors(interceptors ...Interceptor) Option {
+	return func(c *Client) { c.interceptors = append(c.interceptors, interceptors...) }
+}
+
+// New creates a client. By default calls time out after 10s, idempotent requests are retried
+// by DefaultRetryPolicy and a host failing 5 times in a row is cut off for 30s.
+func New(opts ...Option) *Client {
+	c := &Client{
+		timeout: 10 * time.Second,
+		retry:   DefaultRetryPolicy,
+		breaker: DefaultBreakerConfig,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.client == nil {
+		c.client = &http.Client{Transport: defaultTransport()}
+	}
+	c.breakers = newBreakers(c.breaker)
+	c.invoke = chain(c.interceptors, c.client.Do)
+	return c
+}
+
+func defaultTransport() *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = 100
+	t.MaxIdleConnsPerHost = 100
+	return t
+}
+
+// HTTPClient returns the *http.Client sending the requests, e.g. to replace its transport in tests.
+func (c *Client) HTTPClient() *http.Client {
+	return c.client
+}
+
+// Request a call of Client.Do.
+type Request struct {
+	Method string
+	// URL absolute, or relative to the base url of the client
+	URL    string
+	Query  url.Values
+	Header http.Header
+	// Body encoded as json, unless it is a []byte or an io.Reader
+	Body interface{}
+	// Timeout of the call, retries included, overrides the client timeout when not 0
+	Timeout time.Duration
+	// Idempotent allows retrying a POST or PATCH, e.g. one carrying an idempotency key
+	Idempotent bool
+}
+
+// Response the response of Client.Do, its body already read.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Do sends req, retrying and circuit breaking as configured.
+// A non 2xx response is returned together with its error decoded into an *agerrors.Error,
+// errors.Is matches the sentinel of the code answered by the server.
+func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
+	body, contentType, err := encodeBody(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	target, err := c.url(req)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := c.timeout
+	if req.Timeout > 0 {
+		timeout = req.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	retryable := req.Idempotent || isIdempotent(req.Method)
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(ctx, req, target, body, contentType)
+		if !retryable || attempt >= c.retry.MaxAttempts || !shouldRetry(ctx, resp, err) {
+			return resp, err
+		}
+		if err := sleep(ctx, c.retry.backoff(attempt)); err != nil {
+			return resp, err
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, req *Request, target *url.URL, body []byte, contentType string) (*Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, target.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		httpReq.Body = http.NoBody
+	}
+	for k, vs := range req.Header {
+		httpReq.Header[k] = append([]string(nil), vs...)
+	}
+	if contentType != "" && httpReq.Header.Get("Content-Type") == "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	if httpReq.Header.Get("Accept") == "" {
+		httpReq.Header.Set("Accept", "application/json")
+	}
+
+	b := c.breakers.get(target.Host)
+	if !b.allow() {
+		return nil, circuitOpenError(target.Host)
+	}
+	httpResp, err := c.invoke(httpReq)
+	if err == nil {
+		defer httpResp.Body.Close()
+		var respBody []byte
+		if respBody, err = io.ReadAll(httpResp.Body); err == nil {
+			b.done(httpResp.StatusCode >= http.StatusInternalServerError)
+			return newResponse(httpReq, httpResp, respBody)
+		}
+	}
+	if ctx.Err() != nil {
+		// canceled by the caller, says nothing about the host
+		b.abort()
+	} else {
+		b.done(true)
+	}
+	return nil, err
+}
+
+func newResponse(httpReq *http.Request, httpResp *http.Response, respBody []byte) (*Response, error) {
+	resp := &Response{StatusCode: httpResp.StatusCode, Header: httpResp.Header, Body: respBody}
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return resp, decodeError(httpReq, resp)
+	}
+	return resp, nil
+}
+
+func (c *Client) url(req *Request) (*url.URL, error) {
+	raw := req.URL
+	if c.baseURL != "" && !strings.Contains(raw, "://") {
+		raw = c.baseURL + "/" + strings.TrimLeft(raw, "/")
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Query) > 0 {
+		q := u.Query()
+		for k, vs := range req.Query {
+			for _, v := range vs {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u, nil
+}
+
+func encodeBody(body interface{}) ([]byte, string, error) {
+	switch b := body.(type) {
+	case nil:
+		return nil, "", nil
+	case []byte:
+		return b, "", nil
+	case io.Reader:
+		data, err := io.ReadAll(b)
+		return data, "", err
+	}
+	data, err := jsoniter.Marshal(body)
+	return data, "application/json", err
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// CallOption configures one call of the typed helpers.
+type CallOption func(*Request)
+
+// WithHeader sets a request header.
+func WithHeader(key, value string) CallOption {
+	return func(r *Request) {
+		if r.Header == nil {
+			r.Header = http.Header{}
+		}
+		r.Header.Set(key, value)
+	}
+}
+
+// WithQuery adds query parameters.
+func WithQuery(query url.Values) CallOption {
+	return func(r *Request) { r.Query = query }
+}
+
+// WithCallTimeout sets the timeout of the call, retries included.
+func WithCallTimeout(d time.Duration) CallOption {
+	return func(r *Request) { r.Timeout = d }
+}
+
+// WithIdempotent allows retrying a POST or PATCH.
+func WithIdempotent() CallOption {
+	return func(r *Request) { r.Idempotent = true }
+}
+
+// Call sends a request with a json body and decodes the json response into T:
+//
+//	view, err := httpclient.Call[View](ctx, c, http.MethodGet, "/api/data-view/v1/form-view/"+id, nil)
+func Call[T any](ctx context.Context, c *Client, method, url string, body interface{}, opts ...CallOption) (T, error) {
+	var out T
+	req := &Request{Method: method, URL: url, Body: body}
+	for _, opt := range opts {
+		opt(req)
+	}
+	resp, err := c.Do(ctx, req)
+	if err != nil {
+		return out, err
+	}
+	if len(resp.Body) > 0 {
+		err = jsoniter.Unmarshal(resp.Body, &out)
+	}
+	return out, err
+}
+
+// Get sends a GET request, see Call.
+func Get[T any](ctx context.Context, c *Client, url string, opts ...CallOption) (T, error) {
+	return Call[T](ctx, c, http.MethodGet, url, nil, opts...)
+}
+
+// Post sends a POST request, see Call.
+func Post[T any](ctx context.Context, c *Client, url string, body interface{}, opts ...CallOption) (T, error) {
+	return Call[T](ctx, c, http.MethodPost, url, body, opts...)
+}
+
+// Put sends a PUT request, see Call.
+func Put[T any](ctx context.Context, c *Client, url string, body interface{}, opts ...CallOption) (T, error) {
+	return Call[T](ctx, c, http.MethodPut, url, body, opts...)
+}
+
+// Patch sends a PATCH request, see Call.
+func Patch[T any](ctx context.Context, c *Client, url string, body interface{}, opts ...CallOption) (T, error) {
+	return Call[T](ctx, c, http.MethodPatch, url, body, opts...)
+}
+
+// Delete sends a DELETE request, see Call.
+func Delete[T any](ctx context.Context, c *Client, url string, opts ...CallOption) (T, error) {
+	return Call[T](ctx, c, http.MethodDelete, url, nil, opts...)
+}
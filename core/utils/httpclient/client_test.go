@@ -0,0 +1,209 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond, Multiplier: 2}
+
+func TestCall(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/1":
+			assert.Equal(t, "v", r.URL.Query().Get("q"))
+			_, _ = w.Write([]byte(`{"id":"1","name":"alice"}`))
+		case "/users":
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"2","name":"bob"}`))
+		}
+	}))
+	defer srv.Close()
+	c := New(WithBaseURL(srv.URL + "/"))
+
+	u, err := Get[user](context.Background(), c, "/users/1", WithQuery(map[string][]string{"q": {"v"}}))
+	require.NoError(t, err)
+	assert.Equal(t, user{ID: "1", Name: "alice"}, u)
+
+	created, err := Post[*user](context.Background(), c, "users", user{Name: "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, "2", created.ID)
+}
+
+func TestDecodeError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"Public.NotFound","description":"Not Found","detail":{"id":"1"}}`))
+		case "/legacy":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":400001,"message":"bad name","cause":"too long"}`))
+		default:
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte("conflict"))
+		}
+	}))
+	defer srv.Close()
+	c := New(WithBaseURL(srv.URL))
+
+	_, err := Get[user](context.Background(), c, "/rest")
+	assert.True(t, errors.Is(err, agerrors.NewCode(agcodes.CodeNotFound)))
+	assert.Equal(t, http.StatusNotFound, agerrors.HTTPStatus(err))
+	var detail map[string]string
+	require.NoError(t, agerrors.DecodeDetail(err, &detail))
+	assert.Equal(t, "1", detail["id"])
+
+	_, err = Get[user](context.Background(), c, "/legacy")
+	code := agerrors.Code(err)
+	assert.Equal(t, "400001", code.GetErrorCode())
+	assert.Equal(t, "bad name", code.GetDescription())
+	assert.Equal(t, "too long", code.GetCause())
+
+	resp, err := c.Do(context.Background(), &Request{Method: http.MethodGet, URL: "/text"})
+	assert.True(t, agerrors.HasCode(err, agcodes.CodeConflict))
+	assert.Contains(t, err.Error(), "409 conflict")
+	assert.Equal(t, "conflict", string(resp.Body))
+}
+
+func TestRetry(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1)%3 != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer srv.Close()
+	c := New(WithBaseURL(srv.URL), WithRetry(fastRetry), WithBreaker(BreakerConfig{}))
+
+	u, err := Get[user](context.Background(), c, "/")
+	require.NoError(t, err)
+	assert.Equal(t, "1", u.ID)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+
+	// POST is not idempotent
+	atomic.StoreInt32(&calls, 0)
+	_, err = Post[user](context.Background(), c, "/", nil)
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+	_, err = Post[user](context.Background(), c, "/", nil, WithIdempotent())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&calls))
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond, Multiplier: 2, Jitter: 0.5}
+	for i := 0; i < 20; i++ {
+		d := p.backoff(2)
+		assert.True(t, d > 100*time.Millisecond && d <= 200*time.Millisecond, d)
+		assert.LessOrEqual(t, p.backoff(5), 300*time.Millisecond)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	var calls, healthy int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	c := New(WithBaseURL(srv.URL), WithRetry(NoRetry),
+		WithBreaker(BreakerConfig{FailureThreshold: 2, OpenTimeout: 50 * time.Millisecond}))
+	get := func() error {
+		_, err := c.Do(context.Background(), &Request{Method: http.MethodGet, URL: "/"})
+		return err
+	}
+
+	assert.Error(t, get())
+	assert.Error(t, get())
+	err := get()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.True(t, agerrors.HasCode(err, agcodes.CodeServiceUnavailable))
+	assert.EqualValues(t, 2, atomic.LoadInt32(&calls))
+
+	time.Sleep(60 * time.Millisecond)
+	atomic.StoreInt32(&healthy, 1)
+	assert.NoError(t, get())
+	assert.NoError(t, get())
+	assert.EqualValues(t, 4, atomic.LoadInt32(&calls))
+}
+
+func TestTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	c := New(WithBaseURL(srv.URL), WithTimeout(time.Minute))
+
+	start := time.Now()
+	_, err := Get[user](context.Background(), c, "/", WithCallTimeout(50*time.Millisecond))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestInterceptors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"` + r.Header.Get("Authorization") + `|` + r.Header.Get("X-Order") + `"}`))
+	}))
+	defer srv.Close()
+
+	order := func(name string) Interceptor {
+		return func(req *http.Request, next Invoker) (*http.Response, error) {
+			req.Header.Add("X-Order", name)
+			return next(req)
+		}
+	}
+	token := func(ctx context.Context) (string, bool) { return "t0k3n", true }
+	c := New(WithBaseURL(srv.URL), WithInterceptors(BearerAuth(token), order("a"), order("b"), Tracing(), Logging(nil)))
+
+	u, err := Get[user](context.Background(), c, "/")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer t0k3n|a", u.ID)
+
+	u, err = Get[user](context.Background(), c, "/", WithHeader("Authorization", "Basic x"))
+	require.NoError(t, err)
+	assert.Equal(t, "Basic x|a", u.ID)
+}
+
+func TestDefaultTransportVerifiesTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	c := New(WithBaseURL(srv.URL), WithRetry(RetryPolicy{MaxAttempts: 1}))
+	tr, ok := c.HTTPClient().Transport.(*http.Transport)
+	require.True(t, ok)
+	assert.True(t, tr.TLSClientConfig == nil || !tr.TLSClientConfig.InsecureSkipVerify)
+
+	_, err := Get[map[string]interface{}](context.Background(), c, "/")
+	assert.Error(t, err)
+
+	c = New(WithBaseURL(srv.URL), WithHTTPClient(srv.Client()))
+	_, err = Get[map[string]interface{}](context.Background(), c, "/")
+	assert.NoError(t, err)
+}
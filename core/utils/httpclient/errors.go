@@ -0,0 +1,62 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agcodes"
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// maxErrorBody bytes of a non json error body kept in the error text
+const maxErrorBody = 512
+
+// decodeError decodes the body of a non 2xx response into an *agerrors.Error:
+// a rest.HttpError keeps its code, a HTTPError gets its numeric code, any other body
+// gets the public code matching the status.
+func decodeError(req *http.Request, resp *Response) error {
+	if err := agerrors.FromResponse(resp.StatusCode, resp.Body); err != nil {
+		return err
+	}
+
+	var legacy HTTPError
+	if jsoniter.Unmarshal(resp.Body, &legacy) == nil && (legacy.Code != 0 || legacy.Message != "") {
+		var detail interface{}
+		if legacy.Detail != nil {
+			raw, _ := jsoniter.Marshal(legacy.Detail)
+			detail = agerrors.RawDetail(raw)
+		}
+		code := agcodes.New(strconv.Itoa(legacy.Code), legacy.Message, legacy.Cause, "", detail, "")
+		return agerrors.NewCode(agcodes.WithStatus(code, resp.StatusCode, 0))
+	}
+
+	body := string(resp.Body)
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody] + "..."
+	}
+	code := agcodes.WithStatus(statusCode(resp.StatusCode), resp.StatusCode, 0)
+	return agerrors.NewCodeF(code, "%s %s: %d %s", req.Method, req.URL.Redacted(), resp.StatusCode, body)
+}
+
+// statusCode returns the public code of an HTTP status.
+func statusCode(status int) agcodes.Coder {
+	switch status {
+	case http.StatusBadRequest:
+		return agcodes.CodeInvalidParameter
+	case http.StatusUnauthorized:
+		return agcodes.CodeNotAuthorized
+	case http.StatusForbidden:
+		return agcodes.AuthorizationFailure
+	case http.StatusNotFound:
+		return agcodes.CodeNotFound
+	case http.StatusConflict:
+		return agcodes.CodeConflict
+	case http.StatusServiceUnavailable:
+		return agcodes.CodeServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return agcodes.CodeRequestTimeout
+	}
+	return agcodes.CodeInternalError
+}
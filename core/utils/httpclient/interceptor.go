@@ -0,0 +1,103 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/kweaver-ai/idrm-go-frame/core/logx/zapx"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/kweaver-ai/idrm-go-frame/core/utils/httpclient"
+
+// Invoker sends a request, the last one being the *http.Client of the Client.
+type Invoker func(req *http.Request) (*http.Response, error)
+
+// Interceptor wraps every attempt of a call, it may change the request or the response and must call next
+// to go on. The body of the response is read after the whole chain returned.
+type Interceptor func(req *http.Request, next Invoker) (*http.Response, error)
+
+func chain(interceptors []Interceptor, last Invoker) Invoker {
+	invoke := last
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(req *http.Request) (*http.Response, error) {
+			return interceptor(req, next)
+		}
+	}
+	return invoke
+}
+
+// BearerAuth sets the Authorization header from token unless the request has one,
+// e.g. BearerAuth(auth.TokenFromContext) forwards the token of the caller.
+func BearerAuth(token func(ctx context.Context) (string, bool)) Interceptor {
+	return func(req *http.Request, next Invoker) (*http.Response, error) {
+		if req.Header.Get("Authorization") == "" {
+			if t, ok := token(req.Context()); ok && t != "" {
+				req.Header.Set("Authorization", "Bearer "+t)
+			}
+		}
+		return next(req)
+	}
+}
+
+// Tracing starts a client span for every attempt and propagates it in the request headers.
+func Tracing() Interceptor {
+	tracer := otel.Tracer(instrumentationName)
+	return func(req *http.Request, next Invoker) (*http.Response, error) {
+		ctx, span := tracer.Start(req.Context(), "HTTP "+req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("http.request.method", req.Method),
+				attribute.String("server.address", req.URL.Host),
+				attribute.String("url.full", req.URL.Redacted()),
+			))
+		defer span.End()
+
+		req = req.WithContext(ctx)
+		otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+		resp, err := next(req)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return resp, err
+		}
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, resp.Status)
+		}
+		return resp, nil
+	}
+}
+
+// Logging logs every attempt, failures and 5xx responses at warn level. logger nil uses the default logger.
+func Logging(logger zapx.Logger) Interceptor {
+	return func(req *http.Request, next Invoker) (*http.Response, error) {
+		lg := logger
+		if lg == nil {
+			lg = zapx.DefaultLogger()
+		}
+		start := time.Now()
+		resp, err := next(req)
+		kv := []interface{}{
+			"method", req.Method,
+			"url", req.URL.Redacted(),
+			"duration", time.Since(start).String(),
+		}
+		switch {
+		case err != nil:
+			lg.Warnw("http client request failed", append(kv, "error", err.Error())...)
+		case resp.StatusCode >= http.StatusInternalServerError:
+			lg.Warnw("http client request", append(kv, "status", resp.StatusCode)...)
+		default:
+			lg.Infow("http client request", append(kv, "status", resp.StatusCode)...)
+		}
+		return resp, err
+	}
+}
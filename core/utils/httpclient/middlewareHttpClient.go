@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/kweaver-ai/idrm-go-frame/core/errorx/agerrors"
+
 	jsoniter "github.com/json-iterator/go"
 )
 
@@ -26,15 +28,23 @@ func (err HTTPError) Error() string {
 }
 
 // ExHTTPError 其他服务响应的错误结构体
+// 响应体是 HttpError 时, 可以用 errors.Is 匹配错误码, 用 errors.As 取出 *agerrors.Error
 type ExHTTPError struct {
 	Status int
 	Body   []byte
+	// err the error rehydrated from a HttpError body
+	err error
 }
 
 func (err ExHTTPError) Error() string {
 	return string(err.Body)
 }
 
+// Unwrap returns the error rehydrated from the body, nil when the body is not a HttpError.
+func (err ExHTTPError) Unwrap() error {
+	return err.err
+}
+
 // HTTPClient HTTP客户端服务接口
 type HTTPClient interface {
 	Get(ctx context.Context, url string, headers map[string]string) (respParam interface{}, err error)
@@ -143,6 +153,14 @@ func (c *httpClient) httpDo(req *http.Request, headers map[string]string) (respC
 	body, err := io.ReadAll(resp.Body)
 	respCode = resp.StatusCode
 	if (respCode < http.StatusOK) || (respCode >= http.StatusMultipleChoices) {
+		if codeErr := agerrors.FromResponse(respCode, body); codeErr != nil {
+			err = ExHTTPError{
+				Body:   body,
+				Status: respCode,
+				err:    codeErr,
+			}
+			return
+		}
 		httpErr := HTTPError{}
 		err = jsoniter.Unmarshal(body, &httpErr)
 		if err != nil {
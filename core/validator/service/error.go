@@ -76,8 +76,7 @@ func IsBindError(c *gin.Context, err error) (bool, error) {
 	for i := range sliceValidatorErrors {
 		validatorErrors = nil
 		if errors.As(sliceValidatorErrors[i], &validatorErrors) {
-			zhTranslator, _ := uniTrans.GetTranslator("zh")
-			for _, err := range genStructError(validatorErrors.Translate(zhTranslator)) {
+			for _, err := range genStructError(validatorErrors.Translate(getTrans(c))) {
 				errs = append(errs, err)
 			}
 		} else {
@@ -1,11 +1,13 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 	universal_translator "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	"github.com/kweaver-ai/idrm-go-frame/core/i18n"
 	"strings"
 )
 
@@ -89,7 +91,7 @@ func BindJsonAndValid(c *gin.Context, v interface{}) (bool, error) {
 			var validErrors ValidErrors
 			validErrors = append(validErrors, &ValidError{
 				Key:     jsonUnmarshalTypeError.Field,
-				Message: "请输入符合要求的数据类型和数据范围",
+				Message: message(c, "json_type", "请输入符合要求的数据类型和数据范围"),
 			})
 			return false, validErrors
 		}
@@ -98,7 +100,7 @@ func BindJsonAndValid(c *gin.Context, v interface{}) (bool, error) {
 			var validErrors ValidErrors
 			validErrors = append(validErrors, &ValidError{
 				Key:     jsonUnsupportedTypeError.Type.Name(),
-				Message: "不支持的json数据类型",
+				Message: message(c, "json_unsupported_type", "不支持的json数据类型"),
 			})
 			return false, validErrors
 		}
@@ -107,7 +109,7 @@ func BindJsonAndValid(c *gin.Context, v interface{}) (bool, error) {
 			var validErrors ValidErrors
 			validErrors = append(validErrors, &ValidError{
 				Key:     jsonUnsupportedValueError.Str,
-				Message: "不支持的json数据值",
+				Message: message(c, "json_unsupported_value", "不支持的json数据值"),
 			})
 			return false, validErrors
 		}
@@ -125,8 +127,7 @@ func BindStructAndValid(v interface{}) (bool, error) {
 		if !ok {
 			return false, err
 		}
-		trans, _ := uniTrans.FindTranslator("zh")
-		return false, genStructError(validatorErrors.Translate(trans))
+		return false, genStructError(validatorErrors.Translate(GetTranslator(context.Background())))
 	}
 
 	return true, nil
@@ -147,23 +148,38 @@ func genStructError(fields map[string]string) ValidErrors {
 	return errs
 }
 
-func getLocale(c *gin.Context) []string {
-	acceptLanguage := c.GetHeader("Accept-Language")
+// getLocale returns the translator locales of the request: the locale selected by
+// i18n.Middleware (or matched from Accept-Language) and its parents, "zh-Hant-TW"
+// becomes "zh_Hant_TW", "zh_Hant", "zh".
+func getLocale(ctx context.Context) []string {
+	locale := strings.ReplaceAll(i18n.FromContext(ctx), "-", "_")
 	ret := make([]string, 0)
-	for _, lang := range strings.Split(acceptLanguage, ",") {
-		if len(lang) == 0 {
-			continue
+	for locale != "" {
+		ret = append(ret, locale)
+		idx := strings.LastIndex(locale, "_")
+		if idx < 0 {
+			break
 		}
-
-		ret = append(ret, strings.SplitN(lang, ";", 2)[0])
+		locale = locale[:idx]
 	}
 
 	return ret
 }
 
+// GetTranslator returns the shared translator of the locale of ctx, see i18n.FromContext.
+func GetTranslator(ctx context.Context) universal_translator.Translator {
+	trans, _ := uniTrans.FindTranslator(getLocale(ctx)...)
+	return trans
+}
+
 func getTrans(c *gin.Context) universal_translator.Translator {
-	locales := getLocale(c)
+	return GetTranslator(c)
+}
 
-	trans, _ := uniTrans.FindTranslator(locales...)
-	return trans
+// message returns the catalog message "validator.<key>" in the locale of ctx, def when it is missing.
+func message(ctx context.Context, key, def string) string {
+	if msg, ok := i18n.Default().Lookup(i18n.FromContext(ctx), "validator."+key); ok {
+		return msg
+	}
+	return def
 }
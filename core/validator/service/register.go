@@ -5,14 +5,17 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/kweaver-ai/idrm-go-frame/core/i18n"
 	"github.com/kweaver-ai/idrm-go-frame/core/telemetry/log"
 
 	"github.com/go-playground/locales/en"
 	"github.com/go-playground/locales/zh"
+	"github.com/go-playground/locales/zh_Hant_TW"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	enTranslations "github.com/go-playground/validator/v10/translations/en"
 	zhTranslations "github.com/go-playground/validator/v10/translations/zh"
+	zhTwTranslations "github.com/go-playground/validator/v10/translations/zh_tw"
 )
 
 var (
@@ -71,11 +74,15 @@ func registerCustomerValidationAndTranslation(v *validator.Validate, customerVal
 	return nil
 }
 
+// InitTrans builds the shared translators once and registers the default, the custom and the
+// i18n catalog translations. Catalog messages under "validator.<tag>" override the others,
+// so load the catalogs into i18n.Default() before calling it.
 func InitTrans(v *validator.Validate, customerValidators []CustomValidatorObject) error {
 	zhT := zh.New()
-	uniTrans = ut.New(zhT, zhT, en.New())
+	uniTrans = ut.New(zhT, zhT, en.New(), zh_Hant_TW.New())
 	enTran, _ := uniTrans.GetTranslator("en")
 	zhTran, _ := uniTrans.GetTranslator("zh")
+	zhTwTran, _ := uniTrans.GetTranslator("zh_Hant_TW")
 
 	err := enTranslations.RegisterDefaultTranslations(v, enTran)
 	if err != nil {
@@ -89,9 +96,51 @@ func InitTrans(v *validator.Validate, customerValidators []CustomValidatorObject
 		return err
 	}
 
+	err = zhTwTranslations.RegisterDefaultTranslations(v, zhTwTran)
+	if err != nil {
+		log.Errorf("failed to register zh_tw translations, err: %v", err)
+		return err
+	}
+
 	v.RegisterTagNameFunc(registerTagName)
 
-	return registerCustomerValidationAndTranslation(v, customerValidators)
+	if err = registerCustomerValidationAndTranslation(v, customerValidators); err != nil {
+		return err
+	}
+	return registerCatalogTranslation(v, customerValidators)
+}
+
+// registerCatalogTranslation registers the "validator.<tag>" messages of the i18n catalogs
+func registerCatalogTranslation(v *validator.Validate, customerValidators []CustomValidatorObject) error {
+	tranFuncs := make(map[string]validator.TranslationFunc, len(customerValidators))
+	for _, customerValidator := range customerValidators {
+		if customerValidator.TranslationFunc != nil {
+			tranFuncs[customerValidator.Tag] = customerValidator.TranslationFunc
+		}
+	}
+
+	bundle := i18n.Default()
+	for _, locale := range bundle.Locales() {
+		tran, found := uniTrans.GetTranslator(strings.ReplaceAll(locale, "-", "_"))
+		if !found {
+			log.Warnf("no register locale translator, locale: %v", locale)
+			continue
+		}
+		for _, key := range bundle.Keys(locale, "validator.") {
+			tag := strings.TrimPrefix(key, "validator.")
+			msg, _ := bundle.Lookup(locale, key)
+			tranFunc := tranFuncs[tag]
+			if tranFunc == nil {
+				tranFunc = translate
+			}
+			err := v.RegisterTranslation(tag, tran, registerTranslator(tag, msg, true), tranFunc)
+			if err != nil {
+				log.Errorf("failed to register catalog translation, tag: %v, locale: %v, err: %v", tag, locale, err)
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // registerTranslator 为自定义字段添加翻译功能
@@ -111,7 +160,7 @@ func registerTranslator(tag string, msg string, overrides ...bool) validator.Reg
 
 // translate 自定义字段的翻译方法
 func translate(trans ut.Translator, fe validator.FieldError) string {
-	msg, err := trans.T(fe.Tag(), fe.Field())
+	msg, err := trans.T(fe.Tag(), fe.Field(), fe.Param())
 	if err != nil {
 		log.Warnf("warning: error translating FieldError: %s", err)
 		return fe.Error()